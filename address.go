@@ -12,6 +12,12 @@ type Address struct {
 	Address string `db:"email" json:"address"`
 	Raw     string `db:"-" json:"-"`
 	err     error  `db:"-"`
+
+	// The name before RFC 2047 decoding, if it had any encoded-words.
+	encodedName string
+
+	// Problems that didn't make the address invalid.
+	warning error
 }
 
 // String formats an address. It is *not* RFC 2047 encoded!
@@ -105,27 +111,83 @@ func (a Address) WithoutTag() string {
 }
 
 // Valid reports if this email looks valid. This includes some small extra
-// checks for sanity. For example "martin@arp242 is a "valid" email address in
-// the RFC sense, but not in the "something we can send emails to"-sense.
+// checks for sanity, and the RFC 5321 length limits. For example
+// "martin@arp242" is a "valid" email address in the RFC sense, but not in the
+// "something we can send emails to"-sense.
 //
 // TODO: Perhaps consider renaming to CanSend() or Sendable() or Deliverable()?
 //
 // It is also useful if the address wasn't created with ParseList() but directly
 // (e.g. addr := Address{...}).
-func (a *Address) Valid() bool {
+func (a Address) Valid() bool {
+	return a.Check() == nil
+}
+
+// Check the address and return the reason it's not valid, or nil if it's
+// valid. This returns the error from parsing if there was one, and otherwise
+// does the same checks as Valid().
+//
+// Check doesn't modify the address, so it's safe to call concurrently.
+func (a Address) Check() error {
+	if a.err != nil {
+		return a.err
+	}
 	if a.Address == "" || !reValidEmail.MatchString(a.Address) {
-		a.err = ErrNoEmail
-		return false
+		return ErrNoEmail
+	}
+	if !checkLength(a.Address) {
+		return ErrTooLong
 	}
+	return nil
+}
 
-	return a.err == nil
+// Error returns any error that may have been associated with the mail address.
+// This is the same as Check().
+func (a Address) Error() error {
+	return a.Check()
 }
 
-// Error returns any error that may have been associated with the mail address
-func (a *Address) Error() error {
-	if a.Valid() {
-		return nil
-	}
+// Warning returns a problem that was recovered from while parsing, such as a
+// malformed RFC 2047 encoded-word with ParseOptions.Lenient. The address is
+// still valid if there is a warning.
+func (a Address) Warning() error {
+	return a.warning
+}
 
-	return a.err
+// IsDisposable reports if the domain of this address is in the list of
+// disposable domains d.
+func (a Address) IsDisposable(d *DomainList) bool {
+	return d.Contains(a.Domain())
+}
+
+// IsRole reports if this is a role or automated address, such as postmaster@
+// or mailer-daemon@, rather than an address for a person. The patterns are
+// in Roles.
+func (a Address) IsRole() bool {
+	c := Roles.Classify(a)
+	return c == Role || c == Automated
+}
+
+// IsNoReply reports if this is a no-reply address, such as noreply@. The
+// patterns are in Roles.
+func (a Address) IsNoReply() bool {
+	return Roles.Classify(a) == NoReply
+}
+
+// RegistrableDomain gets the registrable domain of the address (also known as
+// "eTLD+1") according to the Public Suffix List; e.g. "example.co.uk" for
+// "martin@support.example.co.uk". See PublicSuffixes().
+func (a Address) RegistrableDomain() string {
+	return PublicSuffixes().RegistrableDomain(a.Domain())
+}
+
+// IsSubdomainOf reports if the domain of this address is domain or a
+// subdomain of it; e.g. "martin@support.example.com" is a subdomain of
+// "example.com".
+func (a Address) IsSubdomainOf(domain string) bool {
+	d, domain := normalizeDomain(a.Domain()), normalizeDomain(domain)
+	if d == "" || domain == "" {
+		return false
+	}
+	return d == domain || strings.HasSuffix(d, "."+domain)
 }
@@ -3,6 +3,8 @@ package mailaddress
 import (
 	"fmt"
 	"mime"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/teamwork/test/diff"
@@ -189,3 +191,201 @@ func TestWithoutTag(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDisposable(t *testing.T) {
+	d := NewDomainList("mailinator.com", "*.example.com")
+	cases := []struct {
+		in       Address
+		expected bool
+	}{
+		{Address{}, false},
+		{Address{Address: "martin@example.com"}, false},
+		{Address{Address: "martin@foo.example.com"}, true},
+		{Address{Address: "martin@MAILINATOR.COM"}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%v", tc.in), func(t *testing.T) {
+			out := tc.in.IsDisposable(d)
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsRole(t *testing.T) {
+	cases := []struct {
+		in                            Address
+		expectedRole, expectedNoReply bool
+	}{
+		{Address{}, false, false},
+		{Address{Address: "martin@example.com"}, false, false},
+		{Address{Address: "postmaster@example.com"}, true, false},
+		{Address{Address: "mailer-daemon@example.com"}, true, false},
+		{Address{Address: "no-reply@example.com"}, false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%v", tc.in), func(t *testing.T) {
+			if out := tc.in.IsRole(); out != tc.expectedRole {
+				t.Errorf("IsRole()\nout:      %#v\nexpected: %#v\n", out, tc.expectedRole)
+			}
+			if out := tc.in.IsNoReply(); out != tc.expectedNoReply {
+				t.Errorf("IsNoReply()\nout:      %#v\nexpected: %#v\n", out, tc.expectedNoReply)
+			}
+		})
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	cases := []struct {
+		in       Address
+		expected string
+	}{
+		{Address{}, ""},
+		{Address{Address: "martin@example.com"}, "example.com"},
+		{Address{Address: "martin@support.EXAMPLE.co.uk"}, "example.co.uk"},
+		{Address{Address: "martin@co.uk"}, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%v", tc.in), func(t *testing.T) {
+			out := tc.in.RegistrableDomain()
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsSubdomainOf(t *testing.T) {
+	cases := []struct {
+		in       Address
+		domain   string
+		expected bool
+	}{
+		{Address{}, "", false},
+		{Address{Address: "martin@example.com"}, "", false},
+		{Address{Address: "martin@example.com"}, "example.com", true},
+		{Address{Address: "martin@support.example.com"}, "EXAMPLE.COM", true},
+		{Address{Address: "martin@a.b.example.com"}, "b.example.com", true},
+		{Address{Address: "martin@notexample.com"}, "example.com", false},
+		{Address{Address: "martin@example.com"}, "support.example.com", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%v", tc.in), func(t *testing.T) {
+			out := tc.in.IsSubdomainOf(tc.domain)
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestValidLength(t *testing.T) {
+	label := func(c string, n int) string { return strings.Repeat(c, n) }
+	cases := []struct {
+		in       string
+		expected error
+	}{
+		// Local part: 64 octets.
+		{label("a", 64) + "@example.com", nil},
+		{label("a", 65) + "@example.com", ErrTooLong},
+		{label("€", 21) + "@example.com", nil},
+		{label("€", 22) + "@example.com", ErrTooLong},
+
+		// Label: 63 octets of the IDNA-encoded form.
+		{"a@" + label("a", 63) + ".com", nil},
+		{"a@" + label("a", 64) + ".com", ErrTooLong},
+		{"a@" + label("bü", 28) + ".com", nil}, // xn--, 63 octets.
+		{"a@" + label("bü", 29) + ".com", ErrTooLong},
+
+		// Entire address: 254 octets.
+		{"a@" + label("a", 63) + "." + label("b", 63) + "." + label("c", 63) + "." + label("d", 56) + ".com", nil},
+		{"a@" + label("a", 63) + "." + label("b", 63) + "." + label("c", 63) + "." + label("d", 57) + ".com", ErrTooLong},
+		{"a@" + label("a", 63) + "." + label("b", 63) + "." + label("c", 63) + "." + label("d", 52) + ".ü.com", ErrTooLong},
+
+		// Still ErrNoEmail if it's not an address at all.
+		{label("a", 300), ErrNoEmail},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%d", len(tc.in)), func(t *testing.T) {
+			a := Address{Address: tc.in}
+			if a.Valid() != (tc.expected == nil) {
+				t.Errorf("Valid() is %t", a.Valid())
+			}
+			if err := a.Error(); err != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", err, tc.expected)
+			}
+
+			_, err := Parse(tc.in)
+			if err != tc.expected {
+				t.Errorf("Parse()\nout:      %v\nexpected: %v\n", err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestAddressCheck(t *testing.T) {
+	cases := []struct {
+		in       Address
+		expected error
+	}{
+		{Address{Address: "martin@example.com"}, nil},
+		{Address{}, ErrNoEmail},
+		{Address{Address: "martin"}, ErrNoEmail},
+		{Address{Address: "martin@example.com", err: ErrInvalidCharacter}, ErrInvalidCharacter},
+		{Address{Address: "martin@" + strings.Repeat("a", 64) + ".com"}, ErrTooLong},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in.Address, func(t *testing.T) {
+			// Calling it more than once or on a pointer shouldn't make a
+			// difference.
+			for i := 0; i < 2; i++ {
+				if err := tc.in.Check(); err != tc.expected {
+					t.Errorf("\nout:      %v\nexpected: %v\n", err, tc.expected)
+				}
+				if err := (&tc.in).Error(); err != tc.expected {
+					t.Errorf("Error()\nout:      %v\nexpected: %v\n", err, tc.expected)
+				}
+			}
+		})
+	}
+}
+
+// Run with -race.
+func TestValidConcurrent(t *testing.T) {
+	l, _ := ParseList(`"Martin" <martin@example.com>, invalid, foo@bar..com, a@example.com`)
+	a := Address{Address: "not-an-address"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = a.Valid()
+				_ = a.Error()
+				for k := range l {
+					_ = l[k].Valid()
+					_ = l[k].Check()
+				}
+				_ = l.Slice()
+				_ = l.Errors()
+				_ = l.ValidAddresses()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if a.err != nil {
+		t.Errorf("err was set: %v", a.err)
+	}
+	if got := len(l.ValidAddresses()); got != 2 {
+		t.Errorf("ValidAddresses: %d", got)
+	}
+}
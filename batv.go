@@ -0,0 +1,142 @@
+package mailaddress
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- the BATV spec uses HMAC-SHA1.
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotBATV is used when an address doesn't have a BATV prvs= tag.
+	ErrNotBATV = errors.New("not a BATV address")
+
+	// ErrBATVForged is used when the signature of a BATV tag is invalid, or
+	// uses an unknown key.
+	ErrBATVForged = errors.New("invalid BATV signature")
+
+	// ErrBATVExpired is used when a BATV tag has expired.
+	ErrBATVExpired = errors.New("BATV tag expired")
+
+	// ErrBATVNoKey is used when signing with a key that doesn't exist.
+	ErrBATVNoKey = errors.New("no BATV key")
+)
+
+// BATVSigner signs envelope senders with Bounce Address Tag Validation
+// prvs= tags, as described in draft-levine-smtp-batv-01. The address
+// user@example.com is signed as:
+//
+//	prvs=KDDDSSSSSS=user@example.com
+//
+// Where K is the key number, DDD the last three digits of the day number the
+// tag expires, and SSSSSS the hex-encoded first three bytes of a HMAC-SHA1
+// hash.
+type BATVSigner struct {
+	// Keys for signing and verifying, indexed by the key number (0-9). This
+	// allows rotating keys: old keys can be kept for verification.
+	Keys map[int][]byte
+
+	// KeyID is the key number to sign with.
+	KeyID int
+
+	// Validity is how long a signed address is valid; the resolution is one
+	// day, and the default is 7 days.
+	Validity time.Duration
+
+	now func() time.Time
+}
+
+// Sign the address. Any existing prvs= tag is replaced.
+func (s BATVSigner) Sign(a Address) (Address, error) {
+	if err := a.Check(); err != nil {
+		return Address{}, err
+	}
+
+	key, ok := s.Keys[s.KeyID]
+	if !ok || s.KeyID < 0 || s.KeyID > 9 {
+		return Address{}, ErrBATVNoKey
+	}
+
+	if _, local, ok := splitBATV(a.Local()); ok {
+		a.Address = local + "@" + a.Domain()
+	}
+
+	tag := fmt.Sprintf("%d%03d", s.KeyID, (s.today()+s.validityDays())%1000)
+	sig := batvHash(key, tag, a.Address)
+	return Address{Name: a.Name, Address: "prvs=" + tag + sig + "=" + a.Address}, nil
+}
+
+// Verify the signature and expiry of a signed address and return the original
+// address.
+func (s BATVSigner) Verify(a Address) (original Address, err error) {
+	tag, local, ok := splitBATV(a.Local())
+	if !ok {
+		return Address{}, ErrNotBATV
+	}
+	original = Address{Name: a.Name, Address: local + "@" + a.Domain()}
+
+	key, ok := s.Keys[int(tag[0]-'0')]
+	if !ok {
+		return Address{}, ErrBATVForged
+	}
+
+	sig := batvHash(key, tag[:4], original.Address)
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(tag[4:])), []byte(sig)) != 1 {
+		return Address{}, ErrBATVForged
+	}
+
+	// The day number wraps around every 1000 days.
+	expires, _ := strconv.Atoi(tag[1:4])
+	if (expires-s.today()%1000+1000)%1000 > s.validityDays() {
+		return Address{}, ErrBATVExpired
+	}
+
+	return original, nil
+}
+
+// splitBATV splits the prvs= tag from the local part.
+func splitBATV(local string) (tag, rest string, ok bool) {
+	if len(local) < 17 || !strings.EqualFold(local[:5], "prvs=") || local[15] != '=' {
+		return "", "", false
+	}
+
+	tag = local[5:15]
+	for i, c := range []byte(tag) {
+		if i < 4 && !(c >= '0' && c <= '9') || i >= 4 && !isHex(c) {
+			return "", "", false
+		}
+	}
+	return tag, local[16:], true
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// batvHash gets the first three bytes of the HMAC-SHA1 of the tag and the
+// lower-cased address.
+func batvHash(key []byte, tag, addr string) string {
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write([]byte(tag + strings.ToLower(addr)))
+	return hex.EncodeToString(mac.Sum(nil)[:3])
+}
+
+func (s BATVSigner) validityDays() int {
+	if s.Validity == 0 {
+		return 7
+	}
+	return int(s.Validity / (24 * time.Hour))
+}
+
+func (s BATVSigner) today() int {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	return int(now().Unix() / 86400)
+}
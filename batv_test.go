@@ -0,0 +1,114 @@
+package mailaddress
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBATV(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := BATVSigner{
+		Keys:  map[int][]byte{1: []byte("secret"), 2: []byte("old")},
+		KeyID: 1,
+		now:   func() time.Time { return now },
+	}
+
+	orig := Address{Name: "Martin", Address: "martin@example.com"}
+	signed, err := s.Sign(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 17683 days since epoch + 7 days validity.
+	if !regexp.MustCompile(`^prvs=1690[0-9a-f]{6}=martin@example\.com$`).MatchString(signed.Address) {
+		t.Errorf("wrong signed address: %v", signed.Address)
+	}
+	if signed.Name != orig.Name {
+		t.Errorf("name not preserved: %v", signed.Name)
+	}
+
+	// Signing again replaces the tag.
+	resigned, err := s.Sign(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resigned != signed {
+		t.Errorf("\nout:      %v\nexpected: %v\n", resigned, signed)
+	}
+
+	for _, addr := range []string{signed.Address, strings.ToUpper(signed.Local()) + "@example.com"} {
+		t.Run(addr, func(t *testing.T) {
+			got, err := s.Verify(Address{Address: addr})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.EqualFold(got.Address, orig.Address) {
+				t.Errorf("\nout:      %v\nexpected: %v\n", got.Address, orig.Address)
+			}
+		})
+	}
+}
+
+func TestBATVVerifyErrors(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := BATVSigner{
+		Keys:  map[int][]byte{1: []byte("secret"), 2: []byte("old")},
+		KeyID: 1,
+		now:   func() time.Time { return now },
+	}
+	sign := func(s BATVSigner, d time.Duration) string {
+		s.now = func() time.Time { return now.Add(d) }
+		a, err := s.Sign(Address{Address: "martin@example.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.Address
+	}
+	withKey := func(id int) BATVSigner {
+		c := s
+		c.KeyID = id
+		return c
+	}
+
+	valid := sign(s, 0)
+	cases := []struct {
+		in       string
+		expected error
+	}{
+		{"martin@example.com", ErrNotBATV},
+		{"prvs=martin@example.com", ErrNotBATV},
+		{"prvs=1abc000000=martin@example.com", ErrNotBATV},
+		{"prvs=1690zzzzzz=martin@example.com", ErrNotBATV},
+		{"prvs=1690000000=martin@example.com", ErrBATVForged},
+		{"prvs=3690000000=martin@example.com", ErrBATVForged},
+		{strings.Replace(valid, "martin@", "other@", 1), ErrBATVForged},
+		{sign(s, -8*24*time.Hour), ErrBATVExpired},
+		{sign(s, -7*24*time.Hour), nil},
+		{sign(s, -1000*24*time.Hour), nil},
+		{sign(withKey(2), 0), nil},
+		{valid, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := s.Verify(Address{Address: tc.in})
+			if err != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestBATVSignErrors(t *testing.T) {
+	s := BATVSigner{Keys: map[int][]byte{1: []byte("secret")}}
+	if _, err := s.Sign(Address{Address: "martin@example.com"}); err != ErrBATVNoKey {
+		t.Errorf("wrong error: %v", err)
+	}
+
+	s.KeyID = 1
+	if _, err := s.Sign(Address{Address: "invalid"}); err != ErrNoEmail {
+		t.Errorf("wrong error: %v", err)
+	}
+}
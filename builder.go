@@ -0,0 +1,93 @@
+package mailaddress
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ListBuilder builds a List without duplicates. Unlike List.Append(), it keeps
+// an index of the addresses so that appending is O(1) rather than O(n).
+//
+// The zero value is ready to use.
+type ListBuilder struct {
+	list  List
+	index map[string]struct{}
+}
+
+// NewListBuilder creates a new ListBuilder with room for size addresses.
+func NewListBuilder(size int) *ListBuilder {
+	return &ListBuilder{
+		list:  make(List, 0, size),
+		index: make(map[string]struct{}, size),
+	}
+}
+
+// Append adds a new Address to the list, as with List.Append(). It reports if
+// the address was added; it's not added if it already exists in the list.
+func (b *ListBuilder) Append(name, address string) bool {
+	return b.AppendAddress(New(name, address))
+}
+
+// AppendAddress adds the Address to the list, unless it already exists in the
+// list. It reports if the address was added.
+func (b *ListBuilder) AppendAddress(a Address) bool {
+	if b.index == nil {
+		b.index = make(map[string]struct{})
+	}
+
+	k := foldKey(a.Address)
+	if _, ok := b.index[k]; ok {
+		return false
+	}
+	b.index[k] = struct{}{}
+	b.list = append(b.list, a)
+	return true
+}
+
+// Contains reports if the address is in the list.
+func (b *ListBuilder) Contains(address string) bool {
+	_, ok := b.index[foldKey(address)]
+	return ok
+}
+
+// Len gets the number of addresses in the list.
+func (b *ListBuilder) Len() int {
+	return len(b.list)
+}
+
+// List gets the list. Later calls to Append() won't modify the returned List.
+func (b *ListBuilder) List() List {
+	return b.list[:len(b.list):len(b.list)]
+}
+
+// listOrNil gets the list, or nil if it's empty.
+func (b *ListBuilder) listOrNil() List {
+	if len(b.list) == 0 {
+		return nil
+	}
+	return b.List()
+}
+
+// foldKey gets a key for s so that foldKey(a) == foldKey(b) if
+// strings.EqualFold(a, b).
+func foldKey(s string) string {
+	// The smallest rune for ASCII letters is always the upper case one.
+	if isASCII(s) {
+		return strings.ToUpper(s)
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		// Use the smallest rune in the case folding orbit, e.g. "K" for "k"
+		// and "K" (Kelvin sign).
+		low := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < low {
+				low = f
+			}
+		}
+		b.WriteRune(low)
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package mailaddress
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListBuilder(t *testing.T) {
+	var b ListBuilder
+	for _, tc := range []struct {
+		name, address string
+		expected      bool
+	}{
+		{"Martin", "martin@example.com", true},
+		{"", "MARTIN@example.COM", false},
+		{"", "foo@example.com", true},
+		{"", "invalid", true},
+		{"", "also invalid", false}, // Both are an empty address.
+		{"", "foo@example.com", false},
+	} {
+		if got := b.Append(tc.name, tc.address); got != tc.expected {
+			t.Errorf("Append(%q): %t", tc.address, got)
+		}
+	}
+
+	l := b.List()
+	b.Append("", "bar@example.com")
+
+	expected := List{
+		{Name: "Martin", Address: "martin@example.com"},
+		{Address: "foo@example.com"},
+		{err: ErrNoEmail},
+	}
+	if !reflect.DeepEqual(l, expected) {
+		t.Errorf("\nout:      %#v\nexpected: %#v\n", l, expected)
+	}
+	if b.Len() != 4 {
+		t.Errorf("Len: %d", b.Len())
+	}
+	if !b.Contains("Bar@Example.com") || b.Contains("baz@example.com") {
+		t.Error("Contains")
+	}
+}
+
+func TestFoldKey(t *testing.T) {
+	words := []string{
+		"a", "A", "k", "K", "K", "s", "S", "ſ", "ß", "ẞ", "σ", "Σ", "ς",
+		"straße", "STRASSE", "STRAẞE", "ǅ", "Ǆ", "ǆ", "é", "É", "\xff", "\xfe", "",
+	}
+	for _, a := range words {
+		for _, b := range words {
+			if (foldKey(a) == foldKey(b)) != strings.EqualFold(a, b) {
+				t.Errorf("%q %q: foldKey %q %q; EqualFold %t",
+					a, b, foldKey(a), foldKey(b), strings.EqualFold(a, b))
+			}
+		}
+	}
+}
+
+func benchmarkList(n int) []string {
+	s := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		s = append(s, fmt.Sprintf("user%d@example.com", i))
+	}
+	return s
+}
+
+func BenchmarkFromSlice(b *testing.B) {
+	for _, n := range []int{10, 1000, 100000} {
+		s := benchmarkList(n)
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				FromSlice(s)
+			}
+		})
+	}
+}
+
+func BenchmarkUniq(b *testing.B) {
+	for _, n := range []int{10, 1000, 100000} {
+		l := make(List, 0, n*2)
+		for _, a := range benchmarkList(n) {
+			l = append(l, Address{Address: a}, Address{Address: strings.ToUpper(a)})
+		}
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.uniq()
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package mailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver looks up DNS records. It's implemented by *net.Resolver.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// StaticResolver is a Resolver which uses in-memory records; this is useful
+// for tests and environments without DNS access. Domains without records
+// return a "not found" *net.DNSError.
+type StaticResolver struct {
+	MX map[string][]*net.MX
+	IP map[string][]net.IPAddr
+}
+
+// LookupMX gets the MX records for name.
+func (r StaticResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	mx, ok := r.MX[strings.ToLower(name)]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return mx, nil
+}
+
+// LookupIPAddr gets the A and AAAA records for host.
+func (r StaticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r.IP[strings.ToLower(host)]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return ip, nil
+}
+
+// Deliverability is the result of a Checker.Check().
+type Deliverability struct {
+	// Domain that was checked, lower-cased and IDNA-encoded (e.g.
+	// "xn--bcher-kva.example").
+	Domain string
+
+	// Deliverable reports if the domain looks like it accepts email.
+	Deliverable bool
+
+	// MX records, sorted by preference.
+	MX []*net.MX
+
+	// Implicit is set if there are no MX records, but there are A or AAAA
+	// records; mail will be delivered to that host (RFC 5321, section 5.1).
+	Implicit bool
+
+	// NullMX is set if the domain explicitly doesn't accept mail with a
+	// "null MX" record (RFC 7505).
+	NullMX bool
+}
+
+// Checker checks if an address is deliverable by looking up the DNS records
+// for the domain. Results are cached per domain. The zero value is usable,
+// and uses net.DefaultResolver.
+//
+// This only checks if the domain can receive email, and not if the mailbox
+// exists.
+type Checker struct {
+	// Resolver to use; the default is net.DefaultResolver.
+	Resolver Resolver
+
+	// CacheTTL is how long to cache deliverable results; the default is 10
+	// minutes.
+	CacheTTL time.Duration
+
+	// NegativeTTL is how long to cache undeliverable results; the default is
+	// 1 minute. Lookup errors are never cached.
+	NegativeTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedDeliverability
+	now   func() time.Time
+}
+
+type cachedDeliverability struct {
+	d       Deliverability
+	expires time.Time
+}
+
+// Check the deliverability of the address. The error is set if the address is
+// invalid or if the lookup failed; an undeliverable domain is not an error.
+func (c *Checker) Check(ctx context.Context, a Address) (Deliverability, error) {
+	if err := a.Check(); err != nil {
+		return Deliverability{}, err
+	}
+
+	// The resolver can't look up Unicode domains.
+	domain, err := domainToASCII(strings.ToLower(a.Domain()))
+	if err != nil {
+		return Deliverability{}, err
+	}
+	now := c.timeNow()
+
+	c.mu.Lock()
+	cached, ok := c.cache[domain]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.d.copy(), nil
+	}
+
+	d, err := c.lookup(ctx, domain)
+	if err != nil {
+		return d, err
+	}
+
+	ttl := c.CacheTTL
+	if ttl == 0 {
+		ttl = 10 * time.Minute
+	}
+	if !d.Deliverable {
+		ttl = c.NegativeTTL
+		if ttl == 0 {
+			ttl = time.Minute
+		}
+	}
+
+	c.mu.Lock()
+	if c.cache == nil {
+		c.cache = make(map[string]cachedDeliverability)
+	}
+	c.cache[domain] = cachedDeliverability{d: d, expires: now.Add(ttl)}
+	c.mu.Unlock()
+
+	return d.copy(), nil
+}
+
+// Flush the cache.
+func (c *Checker) Flush() {
+	c.mu.Lock()
+	c.cache = nil
+	c.mu.Unlock()
+}
+
+func (c *Checker) lookup(ctx context.Context, domain string) (Deliverability, error) {
+	r := c.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+
+	d := Deliverability{Domain: domain}
+	mx, err := r.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return d, err
+	}
+
+	// RFC 7505: a single MX record with an empty (".") exchange.
+	if len(mx) == 1 && (mx[0].Host == "." || mx[0].Host == "") {
+		d.NullMX = true
+		return d, nil
+	}
+
+	if len(mx) > 0 {
+		// The slice is owned by the resolver, so don't modify it.
+		d.MX = copyMX(mx)
+		sort.SliceStable(d.MX, func(i, j int) bool { return d.MX[i].Pref < d.MX[j].Pref })
+		d.Deliverable = true
+		return d, nil
+	}
+
+	// No MX records: fall back to A/AAAA (RFC 5321, section 5.1).
+	ip, err := r.LookupIPAddr(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return d, err
+	}
+	d.Implicit = len(ip) > 0
+	d.Deliverable = d.Implicit
+	return d, nil
+}
+
+// copy makes a copy which doesn't share the MX records, so that callers can't
+// modify the cached result.
+func (d Deliverability) copy() Deliverability {
+	d.MX = copyMX(d.MX)
+	return d
+}
+
+func copyMX(mx []*net.MX) []*net.MX {
+	if mx == nil {
+		return nil
+	}
+	cp := make([]*net.MX, 0, len(mx))
+	for _, m := range mx {
+		m := *m
+		cp = append(cp, &m)
+	}
+	return cp
+}
+
+func (c *Checker) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,149 @@
+package mailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/teamwork/test"
+	"github.com/teamwork/test/diff"
+)
+
+var testResolver = StaticResolver{
+	MX: map[string][]*net.MX{
+		"example.com":           {{Host: "mx2.example.com.", Pref: 20}, {Host: "mx1.example.com.", Pref: 10}},
+		"null.example.com":      {{Host: ".", Pref: 0}},
+		"nomx.example.com":      {},
+		"xn--bcher-kva.example": {{Host: "mx.xn--bcher-kva.example.", Pref: 10}},
+	},
+	IP: map[string][]net.IPAddr{
+		"a.example.com":    {{IP: net.ParseIP("192.0.2.1")}},
+		"nomx.example.com": {{IP: net.ParseIP("2001:db8::1")}},
+	},
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		in          string
+		expected    Deliverability
+		expectedErr string
+	}{
+		{"invalid", Deliverability{}, ErrNoEmail.Error()},
+		{"martin@EXAMPLE.com", Deliverability{
+			Domain:      "example.com",
+			Deliverable: true,
+			MX:          []*net.MX{{Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}},
+		}, ""},
+		{"martin@null.example.com", Deliverability{Domain: "null.example.com", NullMX: true}, ""},
+		{"martin@a.example.com", Deliverability{Domain: "a.example.com", Deliverable: true, Implicit: true}, ""},
+		{"martin@nomx.example.com", Deliverability{Domain: "nomx.example.com", Deliverable: true, Implicit: true}, ""},
+		{"martin@nxdomain.example.com", Deliverability{Domain: "nxdomain.example.com"}, ""},
+		{"martin@BÜCHER.example", Deliverability{
+			Domain:      "xn--bcher-kva.example",
+			Deliverable: true,
+			MX:          []*net.MX{{Host: "mx.xn--bcher-kva.example.", Pref: 10}},
+		}, ""},
+	}
+
+	c := &Checker{Resolver: testResolver}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := c.Check(context.Background(), Address{Address: tc.in})
+			if !test.ErrorContains(err, tc.expectedErr) {
+				t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+			}
+			if d := diff.Diff(tc.expected, got); d != "" {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+type countResolver struct {
+	Resolver
+	n   int
+	err error
+}
+
+func (r *countResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.n++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.Resolver.LookupMX(ctx, name)
+}
+
+func TestCheckCache(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := &countResolver{Resolver: testResolver}
+	c := &Checker{Resolver: r, now: func() time.Time { return now }}
+	check := func(addr string, expectedLookups int) {
+		t.Helper()
+		if _, err := c.Check(context.Background(), Address{Address: addr}); err != nil && r.err == nil {
+			t.Fatal(err)
+		}
+		if r.n != expectedLookups {
+			t.Fatalf("%d lookups; expected %d", r.n, expectedLookups)
+		}
+	}
+
+	check("a@example.com", 1)
+	check("b@EXAMPLE.COM", 1)
+	now = now.Add(11 * time.Minute)
+	check("a@example.com", 2)
+
+	// Negative results expire sooner.
+	check("a@nxdomain.example.com", 3)
+	check("a@nxdomain.example.com", 3)
+	now = now.Add(2 * time.Minute)
+	check("a@nxdomain.example.com", 4)
+
+	c.Flush()
+	check("a@example.com", 5)
+
+	// Errors aren't cached.
+	r.err = errors.New("oh noes")
+	check("a@other.example.com", 6)
+	check("a@other.example.com", 7)
+}
+
+func TestCheckConcurrent(t *testing.T) {
+	r := StaticResolver{MX: map[string][]*net.MX{
+		"example.com": {
+			{Host: "mx3.example.com.", Pref: 30},
+			{Host: "mx2.example.com.", Pref: 20},
+			{Host: "mx1.example.com.", Pref: 10},
+		},
+	}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &Checker{Resolver: r}
+			for j := 0; j < 50; j++ {
+				d, err := c.Check(context.Background(), Address{Address: "martin@example.com"})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if len(d.MX) != 3 || d.MX[0].Pref != 10 {
+					t.Errorf("wrong MX: %v", d.MX)
+				}
+
+				// Modifying the result shouldn't modify the cache.
+				d.MX[0].Pref = 99
+				d.MX[0], d.MX[1] = d.MX[1], d.MX[0]
+			}
+		}()
+	}
+	wg.Wait()
+
+	if mx := r.MX["example.com"]; mx[0].Pref != 30 || mx[2].Pref != 10 {
+		t.Errorf("resolver records modified: %v", mx)
+	}
+}
@@ -0,0 +1,196 @@
+// Command mailaddress parses, validates, and normalizes mail address lists.
+//
+// Every argument is parsed as a list of addresses, as accepted by
+// mailaddress.ParseList(). If there are no arguments then every line from
+// stdin is parsed as a list.
+//
+// The exit code is 1 if there are any invalid addresses, and 2 on usage errors.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/teamwork/mailaddress"
+)
+
+const usage = `usage: mailaddress <command> [list ...]
+
+Every argument is parsed as a list of addresses; lines are read from stdin if
+there are no arguments.
+
+Commands:
+    parse       Print the parsed addresses as JSON, one list per line.
+    validate    Print the validation result for every address.
+    encode      Print the RFC 2047 encoded list, for use in a header.
+    normalize   Print the valid addresses with the domain in lower case.
+    dedupe      Print all unique valid addresses, one per line.
+`
+
+var commands = map[string]func(io.Writer, []mailaddress.List) (bool, error){
+	"parse":     parse,
+	"validate":  validate,
+	"encode":    encode,
+	"normalize": normalize,
+	"dedupe":    dedupe,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "mailaddress: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	input := args[1:]
+	if len(input) == 0 {
+		var err error
+		input, err = readLines(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "mailaddress: reading stdin: %s\n", err)
+			return 2
+		}
+	}
+
+	lists := make([]mailaddress.List, 0, len(input))
+	for _, in := range input {
+		l, _ := mailaddress.ParseList(in)
+		lists = append(lists, l)
+	}
+
+	valid, err := cmd(stdout, lists)
+	if err != nil {
+		fmt.Fprintf(stderr, "mailaddress: %s\n", err)
+		return 2
+	}
+	if !valid {
+		return 1
+	}
+	return 0
+}
+
+// maxLineLength is the maximum length of a line from stdin; a single header
+// with a large list can be much longer than bufio.Scanner's default of 64K.
+const maxLineLength = 64 << 20
+
+// readLines reads all non-empty lines from r.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scan := bufio.NewScanner(r)
+	scan.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	for scan.Scan() {
+		if l := strings.TrimSpace(scan.Text()); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, scan.Err()
+}
+
+// haveErrors reports if there are any errors in the lists.
+func haveErrors(lists []mailaddress.List) bool {
+	for _, l := range lists {
+		if l.Errors() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+type jsonAddress struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Raw     string `json:"raw"`
+	Error   string `json:"error,omitempty"`
+}
+
+func parse(w io.Writer, lists []mailaddress.List) (bool, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, l := range lists {
+		out := make([]jsonAddress, 0, len(l))
+		for _, a := range l {
+			j := jsonAddress{Name: a.Name, Address: a.Address, Raw: a.Raw}
+			if err := a.Error(); err != nil {
+				j.Error = err.Error()
+			}
+			out = append(out, j)
+		}
+		if err := enc.Encode(out); err != nil {
+			return false, err
+		}
+	}
+	return !haveErrors(lists), nil
+}
+
+func validate(w io.Writer, lists []mailaddress.List) (bool, error) {
+	for _, l := range lists {
+		for _, a := range l {
+			addr := a.Address
+			if addr == "" {
+				addr = a.Raw
+			}
+
+			err := a.Error()
+			if err == nil {
+				fmt.Fprintf(w, "ok\t%s\n", addr)
+				continue
+			}
+
+			// Validate() explains in more detail why the address is invalid;
+			// it's not useful if there is no address at all.
+			reasons := []string{err.Error()}
+			if a.Address != "" {
+				for _, v := range mailaddress.Validate(a, mailaddress.ValidateOptions{}) {
+					reasons = append(reasons, v.Error())
+				}
+			}
+			fmt.Fprintf(w, "invalid\t%s\t%s\n", addr, strings.Join(reasons, "; "))
+		}
+	}
+	return !haveErrors(lists), nil
+}
+
+func encode(w io.Writer, lists []mailaddress.List) (bool, error) {
+	for _, l := range lists {
+		fmt.Fprintln(w, l.StringEncoded())
+	}
+	return !haveErrors(lists), nil
+}
+
+func normalize(w io.Writer, lists []mailaddress.List) (bool, error) {
+	for _, l := range lists {
+		valid := l.ValidAddresses()
+		for i, a := range valid {
+			valid[i].Name = strings.TrimSpace(a.Name)
+			valid[i].Address = a.Local() + "@" + strings.ToLower(a.Domain())
+		}
+		fmt.Fprintln(w, valid.String())
+	}
+	return !haveErrors(lists), nil
+}
+
+func dedupe(w io.Writer, lists []mailaddress.List) (bool, error) {
+	var all mailaddress.ListBuilder
+	for _, l := range lists {
+		for _, a := range l.ValidAddresses() {
+			all.AppendAddress(a)
+		}
+	}
+	for _, a := range all.List() {
+		fmt.Fprintln(w, a)
+	}
+	return !haveErrors(lists), nil
+}
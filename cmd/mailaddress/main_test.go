@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		args       []string
+		stdin      string
+		wantOut    string
+		wantStatus int
+	}{
+		{nil, "", "", 2},
+		{[]string{"foo"}, "", "", 2},
+
+		{[]string{"parse", `Martin <martin@example.com>`}, "",
+			`[{"name":"Martin","address":"martin@example.com","raw":"Martin <martin@example.com>"}]` + "\n", 0},
+		{[]string{"parse", `martin`}, "",
+			`[{"name":"","address":"","raw":"martin","error":"unable to find an email address"}]` + "\n", 1},
+
+		{[]string{"validate"}, "a@example.com\n\nb@example.com, c\n",
+			"ok\ta@example.com\nok\tb@example.com\ninvalid\tc\tunable to find an email address\n", 1},
+
+		{[]string{"validate", "a@b..com"}, "",
+			"invalid\ta@b..com\tunable to find an email address; domain has an empty part at position 4\n", 1},
+
+		{[]string{"encode", `Martïn <martin@example.com>, a@example.com`}, "",
+			"=?utf-8?q?Mart=C3=AFn?= <martin@example.com>, a@example.com\n", 0},
+
+		{[]string{"normalize", ` Martin  <Martin@EXAMPLE.com>, x`}, "",
+			"\"Martin\" <Martin@example.com>\n", 1},
+
+		{[]string{"dedupe"}, "a@example.com, b@example.com\nA@example.com\nc@example.com\n",
+			"a@example.com\nb@example.com\nc@example.com\n", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+			if status != tc.wantStatus {
+				t.Errorf("status %d; want %d\nstderr: %s", status, tc.wantStatus, stderr.String())
+			}
+			if out := stdout.String(); out != tc.wantOut {
+				t.Errorf("\nout:      %q\nexpected: %q\n", out, tc.wantOut)
+			}
+		})
+	}
+}
+
+func TestRunLongLine(t *testing.T) {
+	const n = 20000
+	var in strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&in, "user%d@example.com, USER%d@example.com, ", i, i)
+	}
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"dedupe"}, strings.NewReader(in.String()+"\n"), &stdout, &stderr)
+	if status != 0 {
+		t.Fatalf("status %d\nstderr: %s", status, stderr.String())
+	}
+	if lines := strings.Count(stdout.String(), "\n"); lines != n {
+		t.Errorf("%d lines; want %d", lines, n)
+	}
+}
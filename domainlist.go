@@ -0,0 +1,168 @@
+package mailaddress
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed" // For the disposable domains list.
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:embed data/disposable.txt
+var disposableDomains []byte
+
+var (
+	defaultDisposable     *DomainList
+	defaultDisposableOnce sync.Once
+)
+
+// DomainList is a list of domains. Entries starting with "*." match all
+// subdomains of that domain; e.g. "*.example.com" matches "foo.example.com" and
+// "a.b.example.com", but not "example.com".
+//
+// Matching is case-insensitive, and Unicode and IDNA-encoded domains are the
+// same (e.g. "bücher.example" and "xn--bcher-kva.example"). A DomainList is safe for concurrent use, and
+// the list can be replaced at any time with Load().
+type DomainList struct {
+	mu       sync.RWMutex
+	exact    map[string]struct{}
+	wildcard map[string]struct{}
+}
+
+// NewDomainList creates a new list from the given domains.
+func NewDomainList(domains ...string) *DomainList {
+	d := &DomainList{}
+	for _, domain := range domains {
+		d.Add(domain)
+	}
+	return d
+}
+
+// LoadDomainList creates a new list from a newline-delimited list of domains.
+func LoadDomainList(r io.Reader) (*DomainList, error) {
+	d := &DomainList{}
+	return d, d.Load(r)
+}
+
+// DisposableDomains gets a list of well-known disposable ("throwaway") email
+// domains, such as mailinator.com. Every call returns a new copy, so it's safe
+// to modify the list with Add() or Load().
+func DisposableDomains() *DomainList {
+	defaultDisposableOnce.Do(func() {
+		defaultDisposable, _ = LoadDomainList(bytes.NewReader(disposableDomains))
+	})
+
+	d := &DomainList{
+		exact:    make(map[string]struct{}, len(defaultDisposable.exact)),
+		wildcard: make(map[string]struct{}, len(defaultDisposable.wildcard)),
+	}
+	for k := range defaultDisposable.exact {
+		d.exact[k] = struct{}{}
+	}
+	for k := range defaultDisposable.wildcard {
+		d.wildcard[k] = struct{}{}
+	}
+	return d
+}
+
+// Load replaces the list with the newline-delimited list of domains from r.
+// Empty lines and lines starting with # are ignored.
+//
+// The list is only replaced if the entire list was read without errors.
+func (d *DomainList) Load(r io.Reader) error {
+	n := &DomainList{}
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		n.Add(line)
+	}
+	if err := scan.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.exact, d.wildcard = n.exact, n.wildcard
+	d.mu.Unlock()
+	return nil
+}
+
+// Add a domain to the list.
+func (d *DomainList) Add(domain string) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if strings.HasPrefix(domain, "*.") {
+		if d.wildcard == nil {
+			d.wildcard = make(map[string]struct{})
+		}
+		d.wildcard[domain[2:]] = struct{}{}
+		return
+	}
+
+	if d.exact == nil {
+		d.exact = make(map[string]struct{})
+	}
+	d.exact[domain] = struct{}{}
+}
+
+// Len gets the number of entries.
+func (d *DomainList) Len() int {
+	if d == nil {
+		return 0
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.exact) + len(d.wildcard)
+}
+
+// Contains reports if the domain is in the list.
+func (d *DomainList) Contains(domain string) bool {
+	if d == nil {
+		return false
+	}
+
+	domain = normalizeDomain(domain)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, ok := d.exact[domain]; ok {
+		return true
+	}
+	for i := strings.IndexByte(domain, '.'); i > -1; {
+		domain = domain[i+1:]
+		if _, ok := d.wildcard[domain]; ok {
+			return true
+		}
+		i = strings.IndexByte(domain, '.')
+	}
+	return false
+}
+
+// normalizeDomain lower-cases the domain, removes any trailing dot, and
+// converts it to the IDNA-encoded form, so that "bücher.example" and
+// "xn--bcher-kva.example" are the same. Domains which can't be encoded are
+// only lower-cased. A "*." prefix is kept as-is.
+func normalizeDomain(domain string) string {
+	domain = lowerDomain(domain)
+	if strings.HasPrefix(domain, "*.") {
+		return "*." + normalizeDomain(domain[2:])
+	}
+	if ascii, err := domainToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+// lowerDomain lower-cases the domain and removes any trailing dot.
+func lowerDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
@@ -0,0 +1,115 @@
+package mailaddress
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/test"
+)
+
+func TestDomainList(t *testing.T) {
+	d, err := LoadDomainList(strings.NewReader(`
+# Comment
+example.com
+EXAMPLE.net.
+*.wild.example
+  spaces.example  
+xn--bcher-kva.example
+*.München.example
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		in       string
+		expected bool
+	}{
+		{"", false},
+		{"example.com", true},
+		{"Example.COM", true},
+		{"example.com.", true},
+		{"example.net", true},
+		{"spaces.example", true},
+		{"sub.example.com", false},
+		{"example.org", false},
+		{"wild.example", false},
+		{"a.wild.example", true},
+		{"a.b.wild.example", true},
+		{"awild.example", false},
+		{"bücher.example", true},
+		{"BÜCHER.example", true},
+		{"xn--bcher-kva.example", true},
+		{"a.münchen.example", true},
+		{"a.xn--mnchen-3ya.example", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out := d.Contains(tc.in)
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+
+	if d.Len() != 6 {
+		t.Errorf("wrong Len(): %d", d.Len())
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("oh noes") }
+
+func TestDomainListLoad(t *testing.T) {
+	d := NewDomainList("example.com")
+
+	err := d.Load(errReader{})
+	if !test.ErrorContains(err, "oh noes") {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if !d.Contains("example.com") {
+		t.Error("list replaced on error")
+	}
+
+	err = d.Load(strings.NewReader("example.net\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Contains("example.com") || !d.Contains("example.net") {
+		t.Error("list not replaced")
+	}
+
+	var nilList *DomainList
+	if nilList.Contains("example.com") {
+		t.Error("nil list contains domain")
+	}
+	if nilList.Len() != 0 {
+		t.Errorf("wrong Len() for nil list: %d", nilList.Len())
+	}
+}
+
+func TestDisposableDomains(t *testing.T) {
+	d := DisposableDomains()
+	if d.Len() < 10 {
+		t.Fatalf("too few domains: %d", d.Len())
+	}
+	if !d.Contains("mailinator.com") || !d.Contains("foo.mailinator.com") {
+		t.Error("mailinator.com not in list")
+	}
+	if d.Contains("example.com") {
+		t.Error("example.com in list")
+	}
+
+	// Changing the list shouldn't change it for other callers.
+	d.Add("example.com")
+	if err := d.Load(strings.NewReader("example.net")); err != nil {
+		t.Fatal(err)
+	}
+	if d2 := DisposableDomains(); d2.Contains("example.com") || d2.Contains("example.net") ||
+		!d2.Contains("mailinator.com") {
+		t.Error("list modified")
+	}
+}
@@ -9,5 +9,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/teamwork/test v0.0.0-20170823213704-fe7d3af7b993
 	github.com/teamwork/toutf8 v0.0.0-20180417010523-908c4b127591
-	golang.org/x/text v0.3.0 // indirect
+	golang.org/x/net v0.35.0
+	golang.org/x/text v0.22.0
 )
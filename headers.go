@@ -0,0 +1,103 @@
+package mailaddress
+
+import (
+	"bufio"
+	"io"
+	"net/textproto"
+	"strings"
+)
+
+// Envelope contains all the address fields from a message header block.
+type Envelope struct {
+	From    List
+	Sender  List
+	ReplyTo List
+	To      List
+	Cc      List
+	Bcc     List
+
+	ResentFrom   List
+	ResentSender List
+	ResentTo     List
+	ResentCc     List
+	ResentBcc    List
+
+	ReturnPath List
+
+	// Errors contains the errors for every header that had one or more
+	// invalid addresses, keyed by the canonical header name (e.g. "Reply-To").
+	// The errors are multierrors, as returned by List.Errors().
+	Errors map[string]error
+}
+
+// fields maps the canonical header name to the Envelope field.
+func (e *Envelope) fields() map[string]*List {
+	return map[string]*List{
+		"From":          &e.From,
+		"Sender":        &e.Sender,
+		"Reply-To":      &e.ReplyTo,
+		"To":            &e.To,
+		"Cc":            &e.Cc,
+		"Bcc":           &e.Bcc,
+		"Resent-From":   &e.ResentFrom,
+		"Resent-Sender": &e.ResentSender,
+		"Resent-To":     &e.ResentTo,
+		"Resent-Cc":     &e.ResentCc,
+		"Resent-Bcc":    &e.ResentBcc,
+		"Return-Path":   &e.ReturnPath,
+	}
+}
+
+// Recipients gets all addresses from To, Cc, and Bcc. Duplicates are ignored.
+func (e *Envelope) Recipients() List {
+	var l List
+	l = append(l, e.To...)
+	l = append(l, e.Cc...)
+	l = append(l, e.Bcc...)
+	return l.uniq()
+}
+
+// HaveError reports if any of the address fields had an error.
+func (e *Envelope) HaveError() bool {
+	return len(e.Errors) > 0
+}
+
+// ParseHeaders reads a message header block from r and parses all address
+// fields. Reading stops at the first blank line, so it's fine to pass a
+// complete message. Continuation lines are unfolded.
+//
+// The returned error is only set if the header block could not be read; errors
+// in the addresses are recorded in Envelope.Errors.
+func ParseHeaders(r io.Reader) (*Envelope, error) {
+	h, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	// A header block without a trailing blank line is fine.
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return ParseMIMEHeader(h), nil
+}
+
+// ParseMIMEHeader parses all address fields from h. A net/mail.Header can be
+// passed by converting it: ParseMIMEHeader(textproto.MIMEHeader(msg.Header)).
+//
+// Fields that occur more than once are merged in to a single List.
+func ParseMIMEHeader(h textproto.MIMEHeader) *Envelope {
+	e := &Envelope{}
+	for k, l := range e.fields() {
+		values := h.Values(k)
+		if len(values) == 0 {
+			continue
+		}
+
+		*l, _ = ParseList(strings.Join(values, ", "))
+		if err := l.Errors(); err != nil {
+			if e.Errors == nil {
+				e.Errors = make(map[string]error)
+			}
+			e.Errors[k] = err
+		}
+	}
+
+	return e
+}
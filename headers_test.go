@@ -0,0 +1,117 @@
+package mailaddress
+
+import (
+	"fmt"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/test"
+)
+
+func TestParseHeaders(t *testing.T) {
+	cases := []struct {
+		in          string
+		expected    map[string]string
+		expectedErr map[string]bool
+	}{
+		{"", nil, nil},
+		{
+			"From: Martin <martin@example.com>\r\nTo: a@example.com\r\n\r\nbody",
+			map[string]string{
+				"From": `"Martin" <martin@example.com>`,
+				"To":   "a@example.com",
+			},
+			nil,
+		},
+		{
+			// Folded lines.
+			"To: a@example.com,\r\n b@example.com\r\nCc: Martin\r\n\t<martin@example.com>\r\n",
+			map[string]string{
+				"To": "a@example.com, b@example.com",
+				"Cc": `"Martin" <martin@example.com>`,
+			},
+			nil,
+		},
+		{
+			// Repeated fields.
+			"To: a@example.com\nTo: b@example.com, a@example.com\n",
+			map[string]string{"To": "a@example.com, b@example.com"},
+			nil,
+		},
+		{
+			"Reply-To: x@example.com\nResent-From: y@example.com\nResent-Cc: z@example.com\n" +
+				"Return-Path: <bounce@example.com>\nSender: s@example.com\nBcc: b@example.com\n",
+			map[string]string{
+				"Reply-To":    "x@example.com",
+				"Resent-From": "y@example.com",
+				"Resent-Cc":   "z@example.com",
+				"Return-Path": "bounce@example.com",
+				"Sender":      "s@example.com",
+				"Bcc":         "b@example.com",
+			},
+			nil,
+		},
+		{
+			"Return-Path: <>\nTo: a@example.com, invalid\nCc: b@example.com\n",
+			map[string]string{
+				"To": "a@example.com",
+				"Cc": "b@example.com",
+			},
+			map[string]bool{"To": true},
+		},
+		{
+			// Invalid angle-addrs.
+			"To: <a@b>\nCc: <" + strings.Repeat("a", 65) + "@example.com>\nBcc: b@example.com\n",
+			map[string]string{"Bcc": "b@example.com"},
+			map[string]bool{"To": true, "Cc": true},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			env, err := ParseHeaders(strings.NewReader(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for k, l := range env.fields() {
+				got := l.ValidAddresses().String()
+				if got != tc.expected[k] {
+					t.Errorf("%s\nout:      %v\nexpected: %v\n", k, got, tc.expected[k])
+				}
+				if (env.Errors[k] != nil) != tc.expectedErr[k] {
+					t.Errorf("%s: wrong error: %v", k, env.Errors[k])
+				}
+			}
+			if env.HaveError() != (len(tc.expectedErr) > 0) {
+				t.Errorf("wrong HaveError(): %v", env.HaveError())
+			}
+		})
+	}
+}
+
+func TestParseHeadersError(t *testing.T) {
+	_, err := ParseHeaders(strings.NewReader("not a header\n\n"))
+	if !test.ErrorContains(err, "malformed MIME header") {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func TestParseMIMEHeader(t *testing.T) {
+	h := textproto.MIMEHeader{}
+	h.Add("to", "a@example.com")
+	h.Add("cc", "b@example.com, a@example.com")
+	h.Add("bcc", "c@example.com")
+
+	env := ParseMIMEHeader(h)
+	if env.To.String() != "a@example.com" {
+		t.Errorf("wrong To: %v", env.To)
+	}
+
+	got := env.Recipients().String()
+	expected := "a@example.com, b@example.com, c@example.com"
+	if got != expected {
+		t.Errorf("\nout:      %v\nexpected: %v\n", got, expected)
+	}
+}
@@ -0,0 +1,37 @@
+package mailaddress
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// errInvalidUTF8 is used when a domain isn't valid UTF-8; the idna package
+// silently replaces invalid bytes with U+FFFD.
+var errInvalidUTF8 = errors.New("domain is not valid UTF-8")
+
+// domainToASCII converts a domain to the ASCII ("A-label") form used on the
+// wire, e.g. "bücher.example" becomes "xn--bcher-kva.example". Domains which
+// are already ASCII are left as-is.
+//
+// Non-ASCII domains are mapped as described in UTS 46 (e.g. "MÜNCHEN.de"
+// becomes "xn--mnchen-3ya.de").
+func domainToASCII(domain string) (string, error) {
+	if isASCII(domain) {
+		return domain, nil
+	}
+	if !utf8.ValidString(domain) {
+		return "", errInvalidUTF8
+	}
+	return idna.Lookup.ToASCII(domain)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package mailaddress
+
+import "testing"
+
+func TestDomainToASCII(t *testing.T) {
+	cases := []struct {
+		in, expected string
+	}{
+		{"", ""},
+		{"example.com", "example.com"},
+		{"EXAMPLE.com", "EXAMPLE.com"},
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"München.de", "xn--mnchen-3ya.de"},
+		{"ü.русские", "xn--tda.xn--e1affxfam"},
+		{"中国", "xn--fiqs8s"},
+		{"рф", "xn--p1ai"},
+		{"日本語.jp", "xn--wgv71a119e.jp"},
+		{"faß.de", "xn--fa-hia.de"},
+		{"MÜNCHEN.de", "xn--mnchen-3ya.de"},
+		{"ｅｘａｍｐｌｅ.com", "example.com"},
+
+		// Sample strings from RFC 3492, section 7.1.
+		{"ليهمابتكلموشعربي؟", "xn--egbpdaj6bu4bxfgehfvwxn"},
+		{"他们为什么不说中文", "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		{"3年b組金八先生", "xn--3b-ww4c5e180e575a65lsy2b"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out, err := domainToASCII(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+		})
+	}
+
+	if _, err := domainToASCII("a\xffb.com"); err != errInvalidUTF8 {
+		t.Errorf("wrong error: %v", err)
+	}
+	if _, err := domainToASCII("ü\u0378.com"); err == nil {
+		t.Error("no error for disallowed rune")
+	}
+}
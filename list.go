@@ -2,11 +2,14 @@ package mailaddress
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
 )
 
 // List of zero or more addresses.
@@ -57,24 +60,11 @@ func (l *List) UnmarshalJSON(data []byte) error {
 
 // uniq returns only the unique addresses from a list.  Order is preserved
 func (l List) uniq() List {
-	a := List{}
+	b := NewListBuilder(len(l))
 	for _, addr := range l {
-		found := false
-		for _, add := range a {
-			if strings.EqualFold(add.Address, addr.Address) {
-				found = true
-				break
-			}
-		}
-
-		if found {
-			continue
-		}
-
-		a = append(a, addr)
+		b.AppendAddress(addr)
 	}
-
-	return a
+	return b.List()
 }
 
 // StringEncoded makes a string that *is* RFC 2047 encoded.  Duplicates are ignored.
@@ -89,6 +79,9 @@ func (l List) StringEncoded() string {
 
 // Append adds a new Address to the list.  If the address already exists in the
 // list this will be a noop.
+//
+// This needs to check all addresses in the list; use a ListBuilder to add many
+// addresses.
 func (l *List) Append(name, address string) {
 	e := New(name, address)
 
@@ -117,8 +110,19 @@ func (l List) Slice() []string {
 // (github.com/hashicorp/go-multierror).
 func (l List) Errors() (errs error) {
 	for _, a := range l {
-		if !a.Valid() {
-			errs = multierror.Append(errs, a.err)
+		if err := a.Check(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Warnings gets a list of all warnings; see Address.Warning(). The returned
+// error is a multierror (github.com/hashicorp/go-multierror).
+func (l List) Warnings() (errs error) {
+	for _, a := range l {
+		if w := a.Warning(); w != nil {
+			errs = multierror.Append(errs, w)
 		}
 	}
 	return errs
@@ -156,23 +160,188 @@ func (l List) ContainsDomain(domain string) bool {
 	return false
 }
 
+// ContainsOrganization reports if the list contains one or more addresses with
+// the same registrable domain as domain; e.g. "support.example.co.uk" and
+// "example.co.uk" are the same organization.
+func (l List) ContainsOrganization(domain string) bool {
+	org := PublicSuffixes().RegistrableDomain(domain)
+	if org == "" {
+		return false
+	}
+
+	for _, addr := range l {
+		if addr.RegistrableDomain() == org {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterDomains returns a copy of the list without the addresses that have a
+// domain in d.
+func (l List) FilterDomains(d *DomainList) (filtered List) {
+	for _, addr := range l {
+		if !d.Contains(addr.Domain()) {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+// DomainGroup is a group of addresses with the same domain.
+type DomainGroup struct {
+	Domain string
+	List   List
+}
+
+// GroupOptions are options for DomainGroups().
+type GroupOptions struct {
+	// Registrable groups by the registrable domain rather than the full
+	// domain; e.g. "mail.example.com" and "example.com" are both in the
+	// "example.com" group.
+	Registrable bool
+}
+
+// GroupByDomain groups the addresses by domain. The domains are lower-case and
+// IDNA-encoded, so "Bücher.example" and "xn--bcher-kva.example" are both in the
+// "xn--bcher-kva.example" group.
+//
+// Invalid addresses are not included.
+func (l List) GroupByDomain() map[string]List {
+	groups := l.DomainGroups(GroupOptions{})
+	m := make(map[string]List, len(groups))
+	for _, g := range groups {
+		m[g.Domain] = g.List
+	}
+	return m
+}
+
+// DomainGroups groups the addresses by domain like GroupByDomain(), but returns
+// the groups in the order the domains first appear in the list.
+func (l List) DomainGroups(opts GroupOptions) []DomainGroup {
+	var (
+		groups []DomainGroup
+		index  = make(map[string]int)
+	)
+	for _, addr := range l {
+		if !addr.Valid() {
+			continue
+		}
+
+		domain := groupDomain(addr.Domain(), opts)
+		i, ok := index[domain]
+		if !ok {
+			i = len(groups)
+			index[domain] = i
+			groups = append(groups, DomainGroup{Domain: domain})
+		}
+		groups[i].List = append(groups[i].List, addr)
+	}
+	return groups
+}
+
+// groupDomain gets the normalized domain to group by.
+func groupDomain(domain string, opts GroupOptions) string {
+	domain = normalizeDomain(domain)
+	if opts.Registrable {
+		if org := PublicSuffixes().RegistrableDomain(domain); org != "" {
+			domain = org
+		}
+	}
+	return domain
+}
+
+// ClassifiedAddress is an Address with its Class.
+type ClassifiedAddress struct {
+	Address Address
+	Class   Class
+}
+
+// Classify all addresses in the list with the patterns in Roles.
+func (l List) Classify() []ClassifiedAddress {
+	c := make([]ClassifiedAddress, 0, len(l))
+	for _, addr := range l {
+		c = append(c, ClassifiedAddress{Address: addr, Class: Roles.Classify(addr)})
+	}
+	return c
+}
+
 // Sort keys
 const (
 	ByAddress = iota
 	ByName
+	ByDomain // Domain (case-insensitive), and then the local part.
 )
 
-// Sort the list in-place using one of the By* keys.
+// SortKey is one of the By* sort keys.
+type SortKey int8
+
+// ErrSortKey is used when a sort key is invalid.
+var ErrSortKey = errors.New("invalid sort key")
+
+// Sort the list in-place using one of the By* keys. This will panic on invalid
+// keys; use SortBy() to get an error instead.
 func (l List) Sort(key int8) {
-	var sortFunc func(int, int) bool
-	switch key {
-	case ByAddress:
-		sortFunc = func(i, j int) bool { return l[i].Address < l[j].Address }
-	case ByName:
-		sortFunc = func(i, j int) bool { return l[i].Name < l[j].Name }
-	default:
-		panic(fmt.Sprintf("invalid sort key: %v", key))
+	if err := l.SortBy(SortKey(key)); err != nil {
+		panic(err)
+	}
+}
+
+// SortBy sorts the list in-place by one or more keys; addresses which are equal
+// for the first key are sorted by the second key, and so forth. The sort is
+// stable.
+//
+// Names are compared byte-wise; use SortCollate() to sort names in a
+// language-aware way.
+func (l List) SortBy(keys ...SortKey) error {
+	return l.sortBy(strings.Compare, keys)
+}
+
+// SortCollate sorts the list like SortBy(), but compares the names with the
+// collation rules for lang, e.g. "Émile" sorts before "Eric" in French.
+func (l List) SortCollate(lang language.Tag, keys ...SortKey) error {
+	c := collate.New(lang, collate.IgnoreCase)
+	return l.sortBy(c.CompareString, keys)
+}
+
+// SortFunc sorts the list in-place with cmp, which should return a negative
+// number if a < b, a positive number if a > b, and 0 if they're equal. The sort
+// is stable.
+func (l List) SortFunc(cmp func(a, b Address) int) {
+	sort.SliceStable(l, func(i, j int) bool { return cmp(l[i], l[j]) < 0 })
+}
+
+func (l List) sortBy(cmpName func(a, b string) int, keys []SortKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no keys", ErrSortKey)
 	}
 
-	sort.Slice(l, sortFunc)
+	cmps := make([]func(a, b Address) int, 0, len(keys))
+	for _, k := range keys {
+		switch k {
+		case ByAddress:
+			cmps = append(cmps, func(a, b Address) int { return strings.Compare(a.Address, b.Address) })
+		case ByName:
+			cmps = append(cmps, func(a, b Address) int { return cmpName(a.Name, b.Name) })
+		case ByDomain:
+			cmps = append(cmps, func(a, b Address) int {
+				if c := strings.Compare(strings.ToLower(a.Domain()), strings.ToLower(b.Domain())); c != 0 {
+					return c
+				}
+				return strings.Compare(a.Local(), b.Local())
+			})
+		default:
+			return fmt.Errorf("%w: %d", ErrSortKey, k)
+		}
+	}
+
+	l.SortFunc(func(a, b Address) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
 }
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/teamwork/test"
 	"github.com/teamwork/test/diff"
+	"golang.org/x/text/language"
 )
 
 func TestAppend(t *testing.T) {
@@ -177,6 +180,97 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSortBy(t *testing.T) {
+	in := List{
+		{Name: "b", Address: "z@example.com"},
+		{Name: "a", Address: "y@EXAMPLE.com"},
+		{Name: "c", Address: "x@example.org"},
+		{Name: "a", Address: "x@example.com"},
+		{Name: "b", Address: "a@example.net"},
+	}
+	cases := []struct {
+		keys     []SortKey
+		expected []string
+		wantErr  string
+	}{
+		{[]SortKey{ByName}, []string{"y@EXAMPLE.com", "x@example.com", "z@example.com", "a@example.net", "x@example.org"}, ""},
+		{[]SortKey{ByName, ByAddress}, []string{"x@example.com", "y@EXAMPLE.com", "a@example.net", "z@example.com", "x@example.org"}, ""},
+		{[]SortKey{ByDomain}, []string{"x@example.com", "y@EXAMPLE.com", "z@example.com", "a@example.net", "x@example.org"}, ""},
+		{[]SortKey{ByDomain, ByName}, []string{"x@example.com", "y@EXAMPLE.com", "z@example.com", "a@example.net", "x@example.org"}, ""},
+		{[]SortKey{ByAddress}, []string{"a@example.net", "x@example.com", "x@example.org", "y@EXAMPLE.com", "z@example.com"}, ""},
+		{nil, nil, "invalid sort key: no keys"},
+		{[]SortKey{ByName, 42}, nil, "invalid sort key: 42"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%v", tc.keys), func(t *testing.T) {
+			l := append(List{}, in...)
+			err := l.SortBy(tc.keys...)
+			if !test.ErrorContains(err, tc.wantErr) {
+				t.Fatalf("wrong error: %v", err)
+			}
+			if tc.wantErr != "" {
+				if !reflect.DeepEqual(l, in) {
+					t.Errorf("list was modified on error")
+				}
+				return
+			}
+
+			var out []string
+			for _, a := range l {
+				out = append(out, a.Address)
+			}
+			if !reflect.DeepEqual(out, tc.expected) {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSortCollate(t *testing.T) {
+	l := List{{Name: "zoë"}, {Name: "Émile"}, {Name: "Zack"}, {Name: "eric"}}
+
+	bytewise := append(List{}, l...)
+	if err := bytewise.SortBy(ByName); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := bytewise.String(), `"Zack" <>, "eric" <>, "zoë" <>, "Émile" <>`; got != want {
+		t.Errorf("\nout:      %v\nexpected: %v\n", got, want)
+	}
+
+	if err := l.SortCollate(language.English, ByName); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := l.String(), `"Émile" <>, "eric" <>, "Zack" <>, "zoë" <>`; got != want {
+		t.Errorf("\nout:      %v\nexpected: %v\n", got, want)
+	}
+
+	if err := l.SortCollate(language.English, 42); err == nil {
+		t.Error("no error")
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	l := List{{Name: "bb", Address: "1"}, {Name: "a", Address: "2"}, {Name: "cc", Address: "3"}, {Name: "d", Address: "4"}}
+	l.SortFunc(func(a, b Address) int { return len(a.Name) - len(b.Name) })
+	var out []string
+	for _, a := range l {
+		out = append(out, a.Address)
+	}
+	if want := []string{"2", "4", "1", "3"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("\nout:      %v\nexpected: %v\n", out, want)
+	}
+}
+
+func TestSortPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("didn't panic")
+		}
+	}()
+	List{}.Sort(42)
+}
+
 func TestErrors(t *testing.T) {
 	cases := []struct {
 		in                 func() (List, bool)
@@ -322,3 +416,133 @@ func TestContainsDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterDomains(t *testing.T) {
+	d := NewDomainList("mailinator.com", "*.example.com")
+	cases := []struct {
+		in       List
+		expected List
+	}{
+		{List{}, nil},
+		{
+			List{
+				Address{Address: "a@example.com"},
+				Address{Address: "b@mailinator.com"},
+				Address{Address: "c@sub.example.com"},
+				Address{Address: "d@example.net"},
+			},
+			List{Address{Address: "a@example.com"}, Address{Address: "d@example.net"}},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := tc.in.FilterDomains(d)
+			if diff.Diff(tc.expected, out) != "" {
+				t.Errorf(diff.Cmp(tc.expected, out))
+			}
+		})
+	}
+}
+
+func TestListClassify(t *testing.T) {
+	l := List{
+		Address{Address: "martin@example.com"},
+		Address{Address: "info@example.com"},
+		Address{Address: "noreply@example.com"},
+		Address{Address: "mailer-daemon@example.com"},
+	}
+	expected := []ClassifiedAddress{
+		{l[0], Personal},
+		{l[1], Role},
+		{l[2], NoReply},
+		{l[3], Automated},
+	}
+
+	out := l.Classify()
+	if diff.Diff(expected, out) != "" {
+		t.Errorf(diff.Cmp(expected, out))
+	}
+}
+
+func TestContainsOrganization(t *testing.T) {
+	cases := []struct {
+		in       List
+		test     string
+		expected bool
+	}{
+		{List{}, "", false},
+		{List{Address{Address: "foo@example.com"}}, "com", false},
+		{List{Address{Address: "foo@example.com"}}, "example.com", true},
+		{List{Address{Address: "foo@support.example.co.uk"}}, "example.co.uk", true},
+		{List{Address{Address: "foo@example.co.uk"}}, "sales.EXAMPLE.co.uk", true},
+		{List{Address{Address: "foo@example.co.uk"}}, "example.com", false},
+		{List{Address{Address: "foo@a.github.io"}}, "b.github.io", false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
+			out := tc.in.ContainsOrganization(tc.test)
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDomainGroups(t *testing.T) {
+	l, _ := ParseList(`a@example.com, b@mail.EXAMPLE.com, c@Bücher.example, invalid,
+		d@xn--bcher-kva.example, f@example.co.uk, g@www.example.co.uk, h@co.uk,
+		i@www.example.xn--55qx5d.cn, j@example.公司.cn`)
+
+	fmtgroups := func(groups []DomainGroup) string {
+		var out []string
+		for _, g := range groups {
+			out = append(out, g.Domain+": "+strings.Join(g.List.Slice(), " "))
+		}
+		return strings.Join(out, "\n")
+	}
+
+	cases := []struct {
+		opts     GroupOptions
+		expected string
+	}{
+		{GroupOptions{}, `example.com: a@example.com
+mail.example.com: b@mail.EXAMPLE.com
+xn--bcher-kva.example: c@Bücher.example d@xn--bcher-kva.example
+example.co.uk: f@example.co.uk
+www.example.co.uk: g@www.example.co.uk
+co.uk: h@co.uk
+www.example.xn--55qx5d.cn: i@www.example.xn--55qx5d.cn
+example.xn--55qx5d.cn: j@example.公司.cn`},
+		{GroupOptions{Registrable: true}, `example.com: a@example.com b@mail.EXAMPLE.com
+xn--bcher-kva.example: c@Bücher.example d@xn--bcher-kva.example
+example.co.uk: f@example.co.uk g@www.example.co.uk
+co.uk: h@co.uk
+example.xn--55qx5d.cn: i@www.example.xn--55qx5d.cn j@example.公司.cn`},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%+v", tc.opts), func(t *testing.T) {
+			out := fmtgroups(l.DomainGroups(tc.opts))
+			if out != tc.expected {
+				t.Errorf("\nout:\n%v\nexpected:\n%v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGroupByDomain(t *testing.T) {
+	l, _ := ParseList("a@example.com, b@EXAMPLE.com, c@example.net")
+	expected := map[string]List{
+		"example.com": {l[0], l[1]},
+		"example.net": {l[2]},
+	}
+	if out := l.GroupByDomain(); !reflect.DeepEqual(out, expected) {
+		t.Errorf(diff.Cmp(expected, out))
+	}
+
+	if out := (List{}).GroupByDomain(); len(out) != 0 {
+		t.Errorf("not empty: %v", out)
+	}
+}
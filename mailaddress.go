@@ -1,8 +1,30 @@
 package mailaddress
 
+import "net/mail"
+
+// ParseOptions are options for ParseListWithOptions() and ParseWithOptions().
+type ParseOptions struct {
+	// SanitizeName cleans up junk in the display names with SanitizeName().
+	SanitizeName bool
+
+	// Lenient recovers from malformed RFC 2047 encoded-words in the display
+	// name, such as unknown character sets, a missing closing ?=, or an
+	// unencoded comma in the word (which doesn't split the list). Without
+	// this the name is removed and the address gets an error; with this the
+	// name is decoded as well as possible and the address gets a warning (see
+	// Address.Warning()).
+	Lenient bool
+}
+
 // ParseList will parse one or more addresses.
 func ParseList(str string) (l List, haveError bool) {
-	l, haveError = parse(str)
+	return ParseListWithOptions(str, ParseOptions{})
+}
+
+// ParseListWithOptions parses one or more addresses like ParseList(), with the
+// given options.
+func ParseListWithOptions(str string, opts ParseOptions) (l List, haveError bool) {
+	l, haveError = parse(str, opts)
 	if haveError {
 		return l, haveError
 	}
@@ -13,7 +35,13 @@ func ParseList(str string) (l List, haveError bool) {
 // Parse will parse exactly one address. More than one addresses is an error,
 // otherwise it behaves as ParseList().
 func Parse(str string) (Address, error) {
-	list, _ := ParseList(str)
+	return ParseWithOptions(str, ParseOptions{})
+}
+
+// ParseWithOptions parses exactly one address like Parse(), with the given
+// options.
+func ParseWithOptions(str string, opts ParseOptions) (Address, error) {
+	list, _ := ParseListWithOptions(str, opts)
 
 	if len(list) == 0 {
 		return Address{}, ErrNoEmail
@@ -40,18 +68,70 @@ func NewList(name, address string) List {
 	return List{New(name, address)}
 }
 
-// FromMap creates a List from a "map[name string]email string".
-func FromMap(m map[string]string) (l List) {
+// FromMap creates a List from a "map[name string]email string". The list is
+// sorted by name; this is the same as FromMapBy(m, ByName).
+func FromMap(m map[string]string) List {
+	l, _ := FromMapBy(m, ByName)
+	return l
+}
+
+// FromMapBy creates a List from a "map[name string]email string", sorted by one
+// of the By* keys. Entries which are equal for the key are sorted by name.
+//
+// If an address is in the map more than once then only the first one (in the
+// sorted order) is added.
+func FromMapBy(m map[string]string, key SortKey) (List, error) {
+	l := make(List, 0, len(m))
 	for k, v := range m {
-		l.Append(k, v)
+		l = append(l, Address{Name: k, Address: v})
 	}
-	return l
+	if err := l.SortBy(key, ByName); err != nil {
+		return nil, err
+	}
+
+	pairs := make([][2]string, 0, len(l))
+	for _, a := range l {
+		pairs = append(pairs, [2]string{a.Name, a.Address})
+	}
+	return FromPairs(pairs), nil
+}
+
+// FromPairs creates a List from a list of name and address pairs.
+func FromPairs(pairs [][2]string) List {
+	b := NewListBuilder(len(pairs))
+	for _, p := range pairs {
+		b.Append(p[0], p[1])
+	}
+	return b.listOrNil()
 }
 
 // FromSlice creates a List from a []string. Only email addresses are set.
-func FromSlice(s []string) (l List) {
+func FromSlice(s []string) List {
+	b := NewListBuilder(len(s))
 	for _, v := range s {
-		l.Append("", v)
+		b.Append("", v)
 	}
-	return l
+	return b.listOrNil()
+}
+
+// FromAddresses creates a List from a []Address. The addresses are parsed
+// again as with Append(), so any errors are set and the Raw field is cleared.
+func FromAddresses(addrs []Address) List {
+	b := NewListBuilder(len(addrs))
+	for _, a := range addrs {
+		b.Append(a.Name, a.Address)
+	}
+	return b.listOrNil()
+}
+
+// FromMailList creates a List from a list of net/mail addresses, as returned by
+// mail.ParseAddressList(). Nil addresses are skipped.
+func FromMailList(addrs []*mail.Address) List {
+	b := NewListBuilder(len(addrs))
+	for _, a := range addrs {
+		if a != nil {
+			b.Append(a.Name, a.Address)
+		}
+	}
+	return b.listOrNil()
 }
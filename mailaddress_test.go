@@ -3,6 +3,7 @@ package mailaddress
 import (
 	"errors"
 	"fmt"
+	"net/mail"
 	"reflect"
 	"testing"
 
@@ -122,13 +123,20 @@ func TestFromMap(t *testing.T) {
 			map[string]string{"Martin": "martin@example.com"},
 			List{Address{Name: "Martin", Address: "martin@example.com"}},
 		},
-		//{
-		//	map[string]string{"Martin": "martin@example.com", "foo": "bar@example.com"},
-		//	List{
-		//		Address{Name: "Martin", Address: "martin@example.com"},
-		//		Address{Name: "foo", Address: "bar@example.com"},
-		//	},
-		//},
+		{
+			map[string]string{"Martin": "martin@example.com", "foo": "bar@example.com"},
+			List{
+				Address{Name: "Martin", Address: "martin@example.com"},
+				Address{Name: "foo", Address: "bar@example.com"},
+			},
+		},
+		{
+			map[string]string{"b": "x@example.com", "a": "X@example.com", "c": "c@example.com"},
+			List{
+				Address{Name: "a", Address: "X@example.com"},
+				Address{Name: "c", Address: "c@example.com"},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -168,3 +176,102 @@ func TestFromSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestFromMapBy(t *testing.T) {
+	m := map[string]string{
+		"Martin": "martin@example.com",
+		"foo":    "bar@example.com",
+		"dup":    "Bar@example.com",
+		"x":      "invalid",
+		"zzz":    "aaa@EXAMPLE.net",
+	}
+	cases := []struct {
+		key         SortKey
+		expected    List
+		expectedErr string
+	}{
+		{ByName, List{
+			{Name: "Martin", Address: "martin@example.com"},
+			{Name: "dup", Address: "Bar@example.com"},
+			{Name: "x", err: ErrNoEmail},
+			{Name: "zzz", Address: "aaa@EXAMPLE.net"},
+		}, ""},
+		{ByAddress, List{
+			{Name: "dup", Address: "Bar@example.com"},
+			{Name: "zzz", Address: "aaa@EXAMPLE.net"},
+			{Name: "x", err: ErrNoEmail},
+			{Name: "Martin", Address: "martin@example.com"},
+		}, ""},
+		{ByDomain, List{
+			{Name: "dup", Address: "Bar@example.com"},
+			{Name: "Martin", Address: "martin@example.com"},
+			{Name: "zzz", Address: "aaa@EXAMPLE.net"},
+			{Name: "x", err: ErrNoEmail},
+		}, ""},
+		{42, nil, "invalid sort key: 42"},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%d", tc.key), func(t *testing.T) {
+			// Run a few times, as map order is random.
+			for i := 0; i < 10; i++ {
+				got, err := FromMapBy(m, tc.key)
+				if !test.ErrorContains(err, tc.expectedErr) {
+					t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+				}
+				if !reflect.DeepEqual(tc.expected, got) {
+					t.Fatalf(diff.Cmp(tc.expected, got))
+				}
+			}
+		})
+	}
+}
+
+func TestFromPairs(t *testing.T) {
+	got := FromPairs([][2]string{
+		{"Martin", "martin@example.com"},
+		{"", "foo@example.com"},
+		{"Martin again", "MARTIN@example.com"},
+	})
+	expected := List{
+		{Name: "Martin", Address: "martin@example.com"},
+		{Address: "foo@example.com"},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf(diff.Cmp(expected, got))
+	}
+
+	if got := FromPairs(nil); got != nil {
+		t.Errorf("not nil: %#v", got)
+	}
+}
+
+func TestFromAddresses(t *testing.T) {
+	got := FromAddresses([]Address{
+		{Name: "Martin", Address: "martin@example.com", Raw: "Martin <martin@example.com>"},
+		{Address: "martin@example.com"},
+		{Address: "invalid"},
+	})
+	expected := List{
+		{Name: "Martin", Address: "martin@example.com"},
+		{err: ErrNoEmail},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf(diff.Cmp(expected, got))
+	}
+}
+
+func TestFromMailList(t *testing.T) {
+	in, err := mail.ParseAddressList(`Martin <martin@example.com>, foo@example.com, "Foo" <FOO@example.com>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := FromMailList(append(in, nil))
+	expected := List{
+		{Name: "Martin", Address: "martin@example.com"},
+		{Address: "foo@example.com"},
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf(diff.Cmp(expected, got))
+	}
+}
@@ -0,0 +1,183 @@
+package mailaddress
+
+import (
+	"errors"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrNotMailto is used when a URI doesn't have the mailto: scheme.
+var ErrNotMailto = errors.New("not a mailto: URI")
+
+// Mailto is a mailto: URI, as described in RFC 6068.
+type Mailto struct {
+	To      List
+	Cc      List
+	Bcc     List
+	Subject string
+	Body    string
+
+	// Headers are any other header fields (e.g. In-Reply-To).
+	Headers textproto.MIMEHeader
+}
+
+// ParseMailto parses a mailto: URI, e.g.:
+//
+//	mailto:a@example.com,b@example.com?cc=c@example.com&subject=Hello%20world
+//
+// Addresses are parsed with ParseList(); invalid addresses are kept in the
+// lists, and can be checked with List.Errors(). Multiple to, cc, or bcc fields
+// are merged.
+//
+// The returned error is only set if the URI doesn't start with "mailto:" or if
+// the percent-encoding is invalid.
+func ParseMailto(uri string) (*Mailto, error) {
+	if len(uri) < 7 || !strings.EqualFold(uri[:7], "mailto:") {
+		return nil, ErrNotMailto
+	}
+	uri = uri[7:]
+
+	// Fragments have no meaning in mailto: URIs.
+	if i := strings.Index(uri, "#"); i > -1 {
+		uri = uri[:i]
+	}
+
+	path, query, _ := strings.Cut(uri, "?")
+	to, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mailto{}
+	var cc, bcc []string
+	tos := []string{to}
+	for _, field := range strings.Split(query, "&") {
+		if field == "" {
+			continue
+		}
+
+		k, v, _ := strings.Cut(field, "=")
+		// Don't use QueryUnescape, as + is not a space in mailto: URIs.
+		k, err = url.PathUnescape(k)
+		if err != nil {
+			return nil, err
+		}
+		v, err = url.PathUnescape(v)
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(k) {
+		case "to":
+			tos = append(tos, v)
+		case "cc":
+			cc = append(cc, v)
+		case "bcc":
+			bcc = append(bcc, v)
+		case "subject":
+			m.Subject = v
+		case "body":
+			m.Body = v
+		default:
+			if m.Headers == nil {
+				m.Headers = make(textproto.MIMEHeader)
+			}
+			m.Headers.Add(k, v)
+		}
+	}
+
+	m.To = parseFields(tos)
+	m.Cc = parseFields(cc)
+	m.Bcc = parseFields(bcc)
+	return m, nil
+}
+
+func parseFields(fields []string) List {
+	l, _ := ParseList(strings.Join(fields, ","))
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// String formats the mailto: URI, with all the appropriate percent-encoding.
+//
+// Only the addresses are included; the names are lost. Non-ASCII characters
+// in both the local part and the domain are percent-encoded as UTF-8.
+func (m Mailto) String() string {
+	b := strings.Builder{}
+	b.WriteString("mailto:")
+	b.WriteString(escapeMailtoList(m.To))
+
+	var fields []string
+	if len(m.Cc) > 0 {
+		fields = append(fields, "cc="+escapeMailtoList(m.Cc))
+	}
+	if len(m.Bcc) > 0 {
+		fields = append(fields, "bcc="+escapeMailtoList(m.Bcc))
+	}
+	if m.Subject != "" {
+		fields = append(fields, "subject="+escapeMailto(m.Subject, false))
+	}
+	if m.Body != "" {
+		fields = append(fields, "body="+escapeMailto(normalizeNewlines(m.Body), false))
+	}
+
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range m.Headers[k] {
+			fields = append(fields, escapeMailto(k, false)+"="+escapeMailto(v, false))
+		}
+	}
+
+	if len(fields) > 0 {
+		b.WriteString("?")
+		b.WriteString(strings.Join(fields, "&"))
+	}
+	return b.String()
+}
+
+func escapeMailtoList(l List) string {
+	addrs := make([]string, 0, len(l))
+	for _, a := range l {
+		if a.Valid() {
+			addrs = append(addrs, escapeMailto(a.Address, true))
+		}
+	}
+	return strings.Join(addrs, ",")
+}
+
+// escapeMailto percent-encodes everything except the unreserved characters and
+// some-delims from RFC 6068. The + is only left as-is in addresses, as some
+// clients interpret it as a space elsewhere.
+func escapeMailto(s string, isAddr bool) string {
+	const hex = "0123456789ABCDEF"
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			strings.IndexByte("-._~!$'()*;:@", c) > -1,
+			c == '+' && isAddr:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+		}
+	}
+	return b.String()
+}
+
+// normalizeNewlines converts all line breaks to CRLF, as required by RFC 6068.
+func normalizeNewlines(s string) string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	return strings.Replace(s, "\n", "\r\n", -1)
+}
@@ -0,0 +1,144 @@
+package mailaddress
+
+import (
+	"testing"
+
+	"github.com/teamwork/test"
+)
+
+func TestParseMailto(t *testing.T) {
+	cases := []struct {
+		in          string
+		to, cc, bcc string
+		subject     string
+		body        string
+		headers     map[string]string
+		expectedErr string
+	}{
+		{"http://example.com", "", "", "", "", "", nil, "not a mailto"},
+		{"mailto:%zz@example.com", "", "", "", "", "", nil, "invalid URL escape"},
+		{"mailto:", "", "", "", "", "", nil, ""},
+		{"mailto:a@example.com", "a@example.com", "", "", "", "", nil, ""},
+		{"MAILTO:a@example.com,b@example.com", "a@example.com, b@example.com", "", "", "", "", nil, ""},
+		{
+			"mailto:a@example.com?to=b@example.com&cc=c@example.com&bcc=d@example.com,e@example.com",
+			"a@example.com, b@example.com", "c@example.com", "d@example.com, e@example.com",
+			"", "", nil, "",
+		},
+		{
+			"mailto:?to=a@example.com&subject=Hello%20world+x&body=line1%0D%0Aline2",
+			"a@example.com", "", "", "Hello world+x", "line1\r\nline2", nil, "",
+		},
+		{
+			"mailto:a@example.com?In-Reply-To=%3C3469A91.D10AF4C@example.com%3E#frag",
+			"a@example.com", "", "", "", "",
+			map[string]string{"In-Reply-To": "<3469A91.D10AF4C@example.com>"}, "",
+		},
+		{
+			"mailto:%E2%82%AC@%C3%BC.example",
+			"€@ü.example", "", "", "", "", nil, "",
+		},
+		{"mailto:tag%2Bx@example.com", "tag+x@example.com", "", "", "", "", nil, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			m, err := ParseMailto(tc.in)
+			if !test.ErrorContains(err, tc.expectedErr) {
+				t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+			}
+			if tc.expectedErr != "" {
+				return
+			}
+
+			if m.To.String() != tc.to {
+				t.Errorf("wrong To\nout:      %v\nexpected: %v", m.To, tc.to)
+			}
+			if m.Cc.String() != tc.cc {
+				t.Errorf("wrong Cc\nout:      %v\nexpected: %v", m.Cc, tc.cc)
+			}
+			if m.Bcc.String() != tc.bcc {
+				t.Errorf("wrong Bcc\nout:      %v\nexpected: %v", m.Bcc, tc.bcc)
+			}
+			if m.Subject != tc.subject {
+				t.Errorf("wrong Subject\nout:      %#v\nexpected: %#v", m.Subject, tc.subject)
+			}
+			if m.Body != tc.body {
+				t.Errorf("wrong Body\nout:      %#v\nexpected: %#v", m.Body, tc.body)
+			}
+			if len(m.Headers) != len(tc.headers) {
+				t.Errorf("wrong Headers: %#v", m.Headers)
+			}
+			for k, v := range tc.headers {
+				if m.Headers.Get(k) != v {
+					t.Errorf("wrong header %s: %#v", k, m.Headers.Get(k))
+				}
+			}
+		})
+	}
+}
+
+func TestMailtoStringInvalid(t *testing.T) {
+	m, err := ParseMailto("mailto:%22a%20b%22@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := m.String(); out != "mailto:" {
+		t.Errorf("\nout:      %v\nexpected: %v\n", out, "mailto:")
+	}
+}
+
+func TestMailtoString(t *testing.T) {
+	cases := []struct {
+		in       Mailto
+		expected string
+	}{
+		{Mailto{}, "mailto:"},
+		{
+			Mailto{To: List{{Name: "Martin", Address: "martin@example.com"}, {Address: "x+tag@example.com"}}},
+			"mailto:martin@example.com,x+tag@example.com",
+		},
+		{
+			Mailto{
+				To:      List{{Address: "a@example.com"}},
+				Cc:      List{{Address: "b@example.com"}},
+				Bcc:     List{{Address: "c@example.com"}, {Address: "d@example.com"}},
+				Subject: "Hello & goodbye? 1+1=2",
+				Body:    "line1\nline2",
+			},
+			"mailto:a@example.com?cc=b@example.com&bcc=c@example.com,d@example.com" +
+				"&subject=Hello%20%26%20goodbye%3F%201%2B1%3D2&body=line1%0D%0Aline2",
+		},
+		{
+			Mailto{To: List{{Address: "€@ü.example"}, {Address: `we%ird@example.com`}}},
+			"mailto:%E2%82%AC@%C3%BC.example,we%25ird@example.com",
+		},
+		{
+			Mailto{Headers: map[string][]string{"X-B": {"b"}, "X-A": {"a 1", "a 2"}}},
+			"mailto:?X-A=a%201&X-A=a%202&X-B=b",
+		},
+		{
+			// Invalid addresses are skipped.
+			Mailto{To: List{{Address: "a@example.com"}, {Address: "invalid"}, {Address: "b@example.com", err: ErrNoEmail}}},
+			"mailto:a@example.com",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			out := tc.in.String()
+			if out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+
+			// Round-trip.
+			m, err := ParseMailto(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.String() != out {
+				t.Errorf("round-trip failed\nout:      %v\nexpected: %v\n", m.String(), out)
+			}
+		})
+	}
+}
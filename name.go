@@ -0,0 +1,216 @@
+package mailaddress
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PersonName is a display name split in its components.
+type PersonName struct {
+	Prefix   string // Titles such as "Dr." or "Mr."
+	Given    string // First name.
+	Middle   string // All names between the given and family name.
+	Family   string // Last name, including particles such as "van" or "de".
+	Suffix   string // Suffixes such as "Jr." or "III".
+	Nickname string // Quoted nickname, e.g. "Bob" in `Robert "Bob" Smith`.
+}
+
+// IsZero reports if no name was found.
+func (n PersonName) IsZero() bool {
+	return n == PersonName{}
+}
+
+// String formats the name as "Prefix Given Middle Family Suffix"; the nickname
+// is not included.
+func (n PersonName) String() string {
+	parts := make([]string, 0, 5)
+	for _, p := range []string{n.Prefix, n.Given, n.Middle, n.Family, n.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lower-case words without trailing dot.
+var (
+	namePrefixes = map[string]struct{}{
+		"mr": {}, "mrs": {}, "ms": {}, "miss": {}, "mx": {}, "dr": {},
+		"prof": {}, "sir": {}, "dame": {}, "rev": {}, "fr": {},
+	}
+	nameSuffixes = map[string]struct{}{
+		"jr": {}, "sr": {}, "ii": {}, "iii": {}, "iv": {}, "v": {},
+		"phd": {}, "md": {}, "esq": {},
+	}
+	nameParticles = map[string]struct{}{
+		"van": {}, "von": {}, "der": {}, "den": {}, "de": {}, "da": {},
+		"di": {}, "du": {}, "del": {}, "della": {}, "la": {}, "le": {},
+		"ter": {}, "ten": {},
+	}
+)
+
+func isNameWord(m map[string]struct{}, w string) bool {
+	_, ok := m[strings.ToLower(strings.TrimSuffix(w, "."))]
+	return ok
+}
+
+// ParseDisplayName splits a display name in its components. It understands
+// "Given Family" and "Family, Given" forms, titles such as "Dr.", suffixes
+// such as "Jr.", and quoted nicknames.
+//
+// Email addresses in the name are ignored. If the name is only an email address
+// then the name is derived from the local part as with Address.PersonName().
+// The zero value is returned if there is no name or if it has no letters.
+func ParseDisplayName(name string) PersonName {
+	name = strings.Join(strings.Fields(name), " ")
+	for len(name) > 1 && (name[0] == '"' || name[0] == '\'') && name[len(name)-1] == name[0] {
+		name = strings.TrimSpace(name[1 : len(name)-1])
+	}
+	if strings.IndexFunc(name, unicode.IsLetter) == -1 {
+		return PersonName{}
+	}
+
+	// Some clients add the address to the name ("John (john@example.com)"), or
+	// set the name to the address; use the local part if nothing else is left.
+	if strings.Contains(name, "@") {
+		if mail := reFindEmail.FindString(name); mail != "" {
+			name = SanitizeName(name, "")
+			if name == "" {
+				return nameFromLocal(Address{Address: strings.Trim(mail, `"'<>()[]`)})
+			}
+		}
+	}
+
+	var n PersonName
+	name, n.Nickname = nickname(name)
+
+	// "Family, Given [Middle]", "Family, Given, Suffix", or "Given Family,
+	// Suffix".
+	var family string
+	if parts := strings.Split(name, ","); len(parts) > 1 {
+		for len(parts) > 1 && isSuffixes(parts[len(parts)-1]) {
+			n.Suffix = strings.TrimSpace(strings.TrimSpace(parts[len(parts)-1]) + " " + n.Suffix)
+			parts = parts[:len(parts)-1]
+		}
+		if len(parts) > 1 {
+			family = strings.TrimSpace(parts[0])
+			parts = parts[1:]
+		}
+		name = strings.Join(parts, " ")
+	}
+
+	words := strings.Fields(name)
+	for len(words) > 0 && isNameWord(namePrefixes, words[0]) {
+		n.Prefix = strings.TrimSpace(n.Prefix + " " + words[0])
+		words = words[1:]
+	}
+	for len(words) > 1 && isNameWord(nameSuffixes, words[len(words)-1]) {
+		n.Suffix = strings.TrimSpace(words[len(words)-1] + " " + n.Suffix)
+		words = words[:len(words)-1]
+	}
+
+	// A single word after a title is the family name: "Mr. Smith".
+	if family == "" && len(words) == 1 && n.Prefix != "" {
+		family, words = words[0], nil
+	}
+	if family == "" && len(words) > 1 {
+		// Include particles in the family name: "Ludwig van Beethoven".
+		f := len(words) - 1
+		for f > 1 && isNameWord(nameParticles, words[f-1]) {
+			f--
+		}
+		family = strings.Join(words[f:], " ")
+		words = words[:f]
+	}
+
+	n.Family = family
+	if len(words) > 0 {
+		n.Given = words[0]
+		n.Middle = strings.Join(words[1:], " ")
+	}
+	return n
+}
+
+// isSuffixes reports if all words in s are name suffixes.
+func isSuffixes(s string) bool {
+	words := strings.Fields(s)
+	for _, w := range words {
+		if !isNameWord(nameSuffixes, w) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// reNickname matches a quoted nickname; the quotes need to be around entire
+// words, so that "O'Brien" isn't seen as a quote.
+var reNickname = regexp.MustCompile(`(?:^|\s)(?:"([^"]+)"|'([^']+)')(?:\s|$)`)
+
+// nickname removes a quoted nickname from the name.
+func nickname(name string) (string, string) {
+	m := reNickname.FindStringSubmatchIndex(name)
+	if m == nil {
+		return name, ""
+	}
+
+	// Either the "double" or 'single' quoted group matched.
+	start, end := m[2], m[3]
+	if start == -1 {
+		start, end = m[4], m[5]
+	}
+	nick := name[start:end]
+	return strings.Join(strings.Fields(name[:m[0]]+" "+name[m[1]:]), " "), strings.TrimSpace(nick)
+}
+
+// PersonName gets the components of the display name. If there is no display
+// name then it's derived from the local part of the address, e.g.
+// "john.smith@example.com" becomes "John Smith".
+//
+// Role addresses (see RoleSet) don't get a name from the local part.
+func (a Address) PersonName() PersonName {
+	if n := ParseDisplayName(a.Name); !n.IsZero() {
+		return n
+	}
+	return nameFromLocal(a)
+}
+
+// nameFromLocal derives a name from the local part.
+func nameFromLocal(a Address) PersonName {
+	if Roles.Classify(a) != Personal {
+		return PersonName{}
+	}
+
+	local := a.Local()
+	if plus := strings.IndexByte(local, '+'); plus > -1 {
+		local = local[:plus]
+	}
+
+	var words []string
+	for _, w := range strings.FieldsFunc(local, func(r rune) bool { return r == '.' || r == '_' || r == '-' }) {
+		// Remove numbers: "john.smith82".
+		w = strings.TrimFunc(w, unicode.IsDigit)
+		if w != "" {
+			words = append(words, titleCase(w))
+		}
+	}
+
+	var n PersonName
+	switch len(words) {
+	case 0:
+	case 1:
+		n.Given = words[0]
+	default:
+		n.Given = words[0]
+		n.Middle = strings.Join(words[1:len(words)-1], " ")
+		n.Family = words[len(words)-1]
+	}
+	return n
+}
+
+// titleCase upper-cases the first letter and lower-cases the rest.
+func titleCase(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + strings.ToLower(s[size:])
+}
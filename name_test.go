@@ -0,0 +1,95 @@
+package mailaddress
+
+import "testing"
+
+func TestParseDisplayName(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected PersonName
+	}{
+		{"", PersonName{}},
+		{`  "" `, PersonName{}},
+		{"John", PersonName{Given: "John"}},
+		{"John Smith", PersonName{Given: "John", Family: "Smith"}},
+		{`"John  Smith"`, PersonName{Given: "John", Family: "Smith"}},
+		{"John Ronald Reuel Tolkien", PersonName{Given: "John", Middle: "Ronald Reuel", Family: "Tolkien"}},
+		{"Smith, John", PersonName{Given: "John", Family: "Smith"}},
+		{"Smith, John Paul", PersonName{Given: "John", Middle: "Paul", Family: "Smith"}},
+		{"Smith, John, Jr.", PersonName{Given: "John", Family: "Smith", Suffix: "Jr."}},
+		{"John Smith, Jr.", PersonName{Given: "John", Family: "Smith", Suffix: "Jr."}},
+		{"John Smith III", PersonName{Given: "John", Family: "Smith", Suffix: "III"}},
+		{"John Smith Jr. PhD", PersonName{Given: "John", Family: "Smith", Suffix: "Jr. PhD"}},
+		{"Dr. John Smith", PersonName{Prefix: "Dr.", Given: "John", Family: "Smith"}},
+		{"Prof Dr Jane Doe", PersonName{Prefix: "Prof Dr", Given: "Jane", Family: "Doe"}},
+		{"Mr. Smith", PersonName{Prefix: "Mr.", Family: "Smith"}},
+		{"Dr. Smith Jr.", PersonName{Prefix: "Dr.", Family: "Smith", Suffix: "Jr."}},
+		{"Ludwig van Beethoven", PersonName{Given: "Ludwig", Family: "van Beethoven"}},
+		{"Vincent Willem van der Berg", PersonName{Given: "Vincent", Middle: "Willem", Family: "van der Berg"}},
+		{"Van Morrison", PersonName{Given: "Van", Family: "Morrison"}},
+		{`Robert "Bob" Smith`, PersonName{Given: "Robert", Family: "Smith", Nickname: "Bob"}},
+		{`Robert 'Bobby Boy' Smith`, PersonName{Given: "Robert", Family: "Smith", Nickname: "Bobby Boy"}},
+		{"Conan O'Brien", PersonName{Given: "Conan", Family: "O'Brien"}},
+		{"D'Angelo O'Brien", PersonName{Given: "D'Angelo", Family: "O'Brien"}},
+		{"Martïn Tournoij", PersonName{Given: "Martïn", Family: "Tournoij"}},
+
+		// Email as name.
+		{"john.smith@example.com", PersonName{Given: "John", Family: "Smith"}},
+		{"<JOHN_SMITH@example.com>", PersonName{Given: "John", Family: "Smith"}},
+		{"info@example.com", PersonName{}},
+		{"'john.smith@example.com' via Teamwork", PersonName{Given: "John", Family: "Smith"}},
+		{"John Doe (john@x.com)", PersonName{Given: "John", Family: "Doe"}},
+		{"John Doe <john@x.com>", PersonName{Given: "John", Family: "Doe"}},
+		{"'john@x.com' Doe via Teamwork", PersonName{Given: "Doe"}},
+		{"Smith, John [mailto:john@x.com]", PersonName{Given: "John", Family: "Smith"}},
+		{`"(john.smith@example.com)"`, PersonName{Given: "John", Family: "Smith"}},
+		{"[john.smith@example.com]", PersonName{Given: "John", Family: "Smith"}},
+		{"'", PersonName{}},
+		{`"'"`, PersonName{}},
+		{"- 123 -", PersonName{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out := ParseDisplayName(tc.in)
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPersonName(t *testing.T) {
+	cases := []struct {
+		in       Address
+		expected PersonName
+	}{
+		{Address{}, PersonName{}},
+		{Address{Name: "Smith, John", Address: "x@example.com"}, PersonName{Given: "John", Family: "Smith"}},
+		{Address{Address: "john.smith@example.com"}, PersonName{Given: "John", Family: "Smith"}},
+		{Address{Address: "john.r.r.tolkien@example.com"}, PersonName{Given: "John", Middle: "R R", Family: "Tolkien"}},
+		{Address{Address: "JOHN-SMITH82+news@example.com"}, PersonName{Given: "John", Family: "Smith"}},
+		{Address{Address: "martin@example.com"}, PersonName{Given: "Martin"}},
+		{Address{Address: "123@example.com"}, PersonName{}},
+		{Address{Address: "noreply@example.com"}, PersonName{}},
+		{Address{Address: "support@example.com"}, PersonName{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in.String(), func(t *testing.T) {
+			out := tc.in.PersonName()
+			if out != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPersonNameString(t *testing.T) {
+	n := PersonName{Prefix: "Dr.", Given: "John", Middle: "Paul", Family: "Smith", Suffix: "Jr.", Nickname: "JP"}
+	if out, want := n.String(), "Dr. John Paul Smith Jr."; out != want {
+		t.Errorf("\nout:      %v\nexpected: %v\n", out, want)
+	}
+	if !(PersonName{}).IsZero() || n.IsZero() {
+		t.Error("IsZero")
+	}
+}
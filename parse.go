@@ -2,6 +2,7 @@ package mailaddress
 
 import (
 	"errors"
+	"fmt"
 	"mime"
 	"regexp"
 	"strings"
@@ -32,11 +33,11 @@ var (
 		// See RFC 1034, section 3.1, RFC 1035, secion 2.3.1
 		//
 		// - Only allow letters, numbers
-		// - Max size of a single label is 63 characters (RFC specifies bytes, but that's
-		//   not so easy to check AFAIK).
 		// - Need at least two labels
-		`[\p{L}\d-]{1,63}` + // Label
-		`(\.[\p{L}\d-]{1,63})+` + // More labels
+		//
+		// The maximum sizes are in bytes, which is checked in checkLength().
+		`[\p{L}\d-]+` + // Label
+		`(\.[\p{L}\d-]+)+` + // More labels
 
 		// Anchor
 		`$`)
@@ -48,6 +49,10 @@ var (
 	// ErrNoEmail is used when we can't find an email address at all.
 	ErrNoEmail = errors.New("unable to find an email address")
 
+	// ErrTooLong is used when the local part, a domain label, or the entire
+	// address is too long.
+	ErrTooLong = errors.New("email address too long")
+
 	// ErrTooManyEmails is used when too many email addresses were found.
 	ErrTooManyEmails = errors.New("only one address expected")
 
@@ -55,104 +60,158 @@ var (
 	ErrInvalidCharacter = errors.New("invalid character")
 )
 
-func parse(str string) (list List, haveError bool) {
+func parse(str string, opts ParseOptions) (list List, haveError bool) {
 	// Sanitize whitespace
 	str = reSanitizeWhitespace.ReplaceAllString(str, " ")
 
 	list = List{}
-	addr := Address{}
-	inAddress := false
-	inQuote := false
+	p := parser{opts: opts}
 	for i, code := range str {
-		chr := string(code)
+		_, size := utf8.DecodeRuneInString(str[i:])
+		if addr, ok := p.next(code, i+size < len(str)); ok {
+			list = append(list, addr)
+		}
+	}
+	if addr, ok := p.end(); ok {
+		list = append(list, addr)
+	}
 
+	return list, p.haveError
+}
+
+// parser is the state for parsing an address list one character at a time.
+// The input must have whitespace sanitized to a single space.
+type parser struct {
+	opts      ParseOptions
+	addr      Address
+	inAddress bool
+	inQuote   bool
+	prev      rune
+	haveError bool
+
+	// Encoded-word state for ParseOptions.Lenient: the number of ? seen in
+	// the word, and the first special character in it.
+	inWord      bool
+	wordQ       int
+	wordSpecial rune
+}
+
+// next processes the character code; more reports if there is more input after
+// this character. It returns an address and true if this character ended an
+// address.
+func (p *parser) next(code rune, more bool) (Address, bool) {
+	defer func() { p.prev = code }()
+	chr := string(code)
+
+	switch {
+	case code == utf8.RuneError:
+		p.addr.Raw += chr
+		p.addr.err = ErrInvalidEncoding
+		p.haveError = true
+
+	// Don't allow unprintable characters.
+	case code < 0x09 || (code >= 0x0b && code < 0x20):
+		p.addr.Raw += chr
+		p.addr.err = ErrInvalidCharacter
+		p.haveError = true
+
+	// An RFC 2047 encoded-word is a single atom, but some mailers don't encode
+	// special characters such as "," in them; read over those as part of the
+	// name. The word ends at the closing ?= or at whitespace.
+	case p.inWord:
 		switch {
-		case code == utf8.RuneError:
-			addr.Raw += chr
-			addr.err = ErrInvalidEncoding
-			haveError = true
-
-		// Don't allow unprintable characters.
-		case code < 0x09 || (code >= 0x0b && code < 0x20):
-			addr.Raw += chr
-			addr.err = ErrInvalidCharacter
-			haveError = true
-
-		case chr == `\`:
-			// Ignore
-			addr.Raw += `\`
-
-		// Quote
-		// TODO: support quoting the local part too.
-		case chr == `"`:
-			addr.Raw += chr
-
-			// Escaped
-			if inQuote && i > 0 && str[i-1] == '\\' {
-				if inAddress {
-					addr.Address += chr
-				} else {
-					addr.Name += chr
-				}
-				continue
+		case code == '?':
+			p.wordQ++
+		case code == '=' && p.prev == '?' && p.wordQ >= 4, code == ' ':
+			p.inWord = false
+		case p.wordSpecial == 0 && strings.ContainsRune(`,;<>"\`, code):
+			p.wordSpecial = code
+		}
+		p.addr.Raw += chr
+		p.addr.Name += chr
+
+	case chr == `\`:
+		// Ignore
+		p.addr.Raw += `\`
+
+	// Quote
+	// TODO: support quoting the local part too.
+	case chr == `"`:
+		p.addr.Raw += chr
+
+		// Escaped
+		if p.inQuote && p.prev == '\\' {
+			if p.inAddress {
+				p.addr.Address += chr
+			} else {
+				p.addr.Name += chr
 			}
+			return Address{}, false
+		}
 
-			inQuote = !inQuote
-
-		// Start <angl-addr>
-		case !inQuote && chr == "<":
-			addr.Raw += "<"
-			inAddress = true
-
-		// End <angl-addr>
-		case !inQuote && chr == ">":
-			addr.Raw += ">"
-			// we've observed name including `<>`
-			if i < len(str)-1 && !addr.Valid() {
-				addr.Name += " " + addr.Address
-				addr.Address = ""
-				addr.err = nil // valid is not idempoent
-			}
+		p.inQuote = !p.inQuote
+
+	// Start <angl-addr>
+	case !p.inQuote && chr == "<":
+		p.addr.Raw += "<"
+		p.inAddress = true
+
+	// End <angl-addr>
+	case !p.inQuote && chr == ">":
+		p.addr.Raw += ">"
+		// we've observed name including `<>`
+		if more && !p.addr.Valid() {
+			p.addr.Name += " " + p.addr.Address
+			p.addr.Address = ""
+			p.addr.err = nil
+		}
 
-			inAddress = false
+		p.inAddress = false
 
-		// Next <address>
-		case !inQuote && (chr == "," || chr == ";" || inAddress && unicode.IsSpace(code)): // ';' introduced by outlook
-			haveError = end(&addr) || haveError
-			if addr.Name != "" || addr.Address != "" || addr.err != nil {
-				list = append(list, addr)
-			}
-			addr = Address{}
-
-		// We've seen <angl-addr> but more data :-/
-		case !inQuote && !inAddress && addr.Address != "" && !unicode.IsSpace(code):
-			// Set error and read over it.
-			if addr.err == nil {
-				addr.err = ErrInvalidCharacter
-				haveError = true
-			}
+	// Next <address>
+	case !p.inQuote && (chr == "," || chr == ";" || p.inAddress && unicode.IsSpace(code)): // ';' introduced by outlook
+		return p.end()
 
-		// Append to address.
-		case inAddress:
-			addr.Raw += chr
-			addr.Address += chr
+	// We've seen <angl-addr> but more data :-/
+	case !p.inQuote && !p.inAddress && p.addr.Address != "" && !unicode.IsSpace(code):
+		// Set error and read over it.
+		if p.addr.err == nil {
+			p.addr.err = ErrInvalidCharacter
+			p.haveError = true
+		}
 
-		// Append to name.
-		default:
-			addr.Raw += chr
-			addr.Name += chr
+	// Append to address.
+	case p.inAddress:
+		p.addr.Raw += chr
+		p.addr.Address += chr
+
+	// Append to name.
+	default:
+		p.addr.Raw += chr
+		p.addr.Name += chr
+		if p.opts.Lenient && !p.inQuote && code == '?' && p.prev == '=' {
+			p.inWord, p.wordQ = true, 1
 		}
 	}
 
-	haveError = end(&addr) || haveError
-	if addr.Name != "" || addr.Address != "" || addr.err != nil {
-		list = append(list, addr)
-	}
+	return Address{}, false
+}
 
-	return list, haveError
+// end the current address. It returns the address and true if there was
+// anything to return.
+func (p *parser) end() (Address, bool) {
+	addr := p.addr
+	p.addr = Address{}
+	p.haveError = end(&addr, p.opts) || p.haveError
+
+	if p.wordSpecial != 0 && addr.warning == nil {
+		addr.warning = fmt.Errorf("%w: unencoded %q in encoded-word", ErrEncodedWord, p.wordSpecial)
+	}
+	p.inWord, p.wordQ, p.wordSpecial = false, 0, 0
+	return addr, addr.Name != "" || addr.Address != "" || addr.err != nil
 }
 
-func end(a *Address) (goterror bool) {
+func end(a *Address, opts ParseOptions) (goterror bool) {
 	a.Name = strings.TrimSpace(a.Name)
 	a.Raw = strings.TrimSpace(a.Raw)
 
@@ -166,11 +225,18 @@ func end(a *Address) (goterror bool) {
 	// their special meaning), so this is why we do this here.
 	decoder := mime.WordDecoder{CharsetReader: toutf8.Reader}
 	decoded, err := decoder.DecodeHeader(a.Name)
+	if opts.Lenient && (err != nil || strings.Contains(decoded, "=?")) {
+		decoded, a.warning = decodeLenient(a.Name)
+		err = nil
+	}
 	if err != nil {
 		a.err = err
 		a.Name = ""
 		return true
 	}
+	if decoded != a.Name {
+		a.encodedName = a.Name
+	}
 	a.Name = decoded
 
 	// It was just an <addr-spec> and not a <angle-addr> or <name-addr>.
@@ -193,12 +259,19 @@ func end(a *Address) (goterror bool) {
 		}
 
 		a.Name = ""
+		a.encodedName = ""
+		a.warning = nil
+	}
+
+	if opts.SanitizeName {
+		a.Name = SanitizeName(a.Name, a.Address)
 	}
 
-	// Includes some sanity checks; it sets Error.
+	// Includes some sanity checks; store the result so that it doesn't need
+	// to be checked again later.
 	if a.Address != "" {
-		e := a.Valid()
-		goterror = goterror && e
+		a.err = a.Check()
+		goterror = goterror && a.err == nil
 	}
 
 	return goterror
@@ -0,0 +1,206 @@
+package mailaddress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRule is used when a policy rule can't be parsed.
+var ErrInvalidRule = errors.New("invalid policy rule")
+
+// PolicyRule is a single rule in a Policy.
+type PolicyRule struct {
+	// Allow or deny addresses matching this rule.
+	Allow bool
+
+	// Pattern is one of:
+	//
+	//   user@example.com    A single address; tags are ignored.
+	//   example.com         All addresses on this domain.
+	//   *.example.com       All addresses on subdomains of example.com.
+	//   *                   All addresses.
+	Pattern string
+
+	// Line is the line number of the rule as passed to CompilePolicy(), starting
+	// at 1.
+	Line int
+}
+
+// String formats the rule in the same format as accepted by CompilePolicy().
+func (r PolicyRule) String() string {
+	if r.Allow {
+		return "allow " + r.Pattern
+	}
+	return "deny " + r.Pattern
+}
+
+// Specificity ranks; higher is more specific.
+const (
+	specAll = iota
+	specWildcard
+	specDomain
+	specAddress
+)
+
+// specificity gets the rank of the rule, and the length of the pattern for
+// comparing wildcards.
+func (r PolicyRule) specificity() (int, int) {
+	switch {
+	case r.Pattern == "*":
+		return specAll, 0
+	case strings.HasPrefix(r.Pattern, "*."):
+		return specWildcard, len(r.Pattern)
+	case strings.Contains(r.Pattern, "@"):
+		return specAddress, 0
+	default:
+		return specDomain, 0
+	}
+}
+
+func (r PolicyRule) match(addr, domain string) bool {
+	switch s, _ := r.specificity(); s {
+	case specAll:
+		return true
+	case specWildcard:
+		return strings.HasSuffix(domain, r.Pattern[1:])
+	case specAddress:
+		return strings.EqualFold(addr, r.Pattern)
+	default:
+		return strings.EqualFold(domain, r.Pattern)
+	}
+}
+
+// Policy allows or denies addresses based on a list of rules.
+//
+// The most specific matching rule is used: an address rule is more specific
+// than a domain rule, which is more specific than a *.domain rule (a longer
+// *.domain is more specific than a shorter one), which is more specific than
+// *. If an address matches both an allow and deny rule with the same
+// specificity then it's denied.
+//
+// If no rule matches, the address is denied if there are any allow rules, and
+// allowed otherwise. Invalid addresses are always denied.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// Decision is the result of evaluating an Address against a Policy.
+type Decision struct {
+	Address Address
+	Allowed bool
+
+	// Rule that matched; this is nil if no rule matched.
+	Rule *PolicyRule
+}
+
+// CompilePolicy creates a new Policy from a list of rules in the form of
+// "allow <pattern>" or "deny <pattern>"; see PolicyRule for the patterns.
+// Empty rules and rules starting with # are ignored.
+func CompilePolicy(rules ...string) (*Policy, error) {
+	p := &Policy{}
+	for i, line := range rules {
+		lineNo := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		f := strings.Fields(line)
+		if len(f) != 2 {
+			return nil, fmt.Errorf("%w on line %d: %q", ErrInvalidRule, lineNo, line)
+		}
+
+		r := PolicyRule{Line: lineNo, Pattern: strings.ToLower(f[1])}
+		switch strings.ToLower(f[0]) {
+		case "allow":
+			r.Allow = true
+		case "deny":
+		default:
+			return nil, fmt.Errorf("%w on line %d: unknown action %q", ErrInvalidRule, lineNo, f[0])
+		}
+
+		// *@example.com is the same as example.com
+		r.Pattern = strings.TrimPrefix(r.Pattern, "*@")
+
+		if strings.Contains(r.Pattern, "@") {
+			a := Address{Address: r.Pattern}
+			r.Pattern = a.WithoutTag()
+			if r.Pattern == "" {
+				return nil, fmt.Errorf("%w on line %d: invalid address %q", ErrInvalidRule, lineNo, f[1])
+			}
+		} else if !validWildcard(r.Pattern) {
+			return nil, fmt.Errorf("%w on line %d: invalid wildcard %q", ErrInvalidRule, lineNo, f[1])
+		} else {
+			r.Pattern = normalizeDomain(r.Pattern)
+		}
+
+		p.Rules = append(p.Rules, r)
+	}
+	return p, nil
+}
+
+// validWildcard reports if the * is only used as "*" or "*.domain".
+func validWildcard(pattern string) bool {
+	i := strings.LastIndex(pattern, "*")
+	return i == -1 || pattern == "*" || (i == 0 && len(pattern) > 2 && pattern[1] == '.')
+}
+
+// Check the address against the policy.
+func (p *Policy) Check(a Address) Decision {
+	d := Decision{Address: a}
+	addr := a.WithoutTag()
+	if addr == "" {
+		return d
+	}
+	domain := normalizeDomain(a.Domain())
+
+	var (
+		best              *PolicyRule
+		bestSpec, bestLen int
+		haveAllow         bool
+	)
+	for i := range p.Rules {
+		r := &p.Rules[i]
+		haveAllow = haveAllow || r.Allow
+		if !r.match(addr, domain) {
+			continue
+		}
+
+		spec, l := r.specificity()
+		switch {
+		case best == nil, spec > bestSpec, spec == bestSpec && l > bestLen,
+			spec == bestSpec && l == bestLen && best.Allow && !r.Allow:
+			best, bestSpec, bestLen = r, spec, l
+		}
+	}
+
+	if best == nil {
+		d.Allowed = !haveAllow
+		return d
+	}
+	d.Allowed = best.Allow
+	d.Rule = best
+	return d
+}
+
+// Evaluate all addresses in the list.
+func (p *Policy) Evaluate(l List) []Decision {
+	d := make([]Decision, 0, len(l))
+	for _, a := range l {
+		d = append(d, p.Check(a))
+	}
+	return d
+}
+
+// Partition the list in allowed and rejected addresses.
+func (p *Policy) Partition(l List) (allowed, rejected List) {
+	for _, d := range p.Evaluate(l) {
+		if d.Allowed {
+			allowed = append(allowed, d.Address)
+		} else {
+			rejected = append(rejected, d.Address)
+		}
+	}
+	return allowed, rejected
+}
@@ -0,0 +1,130 @@
+package mailaddress
+
+import (
+	"testing"
+
+	"github.com/teamwork/test"
+)
+
+func TestCompilePolicy(t *testing.T) {
+	cases := []struct {
+		in          []string
+		expected    []PolicyRule
+		expectedErr string
+	}{
+		{nil, nil, ""},
+		{[]string{"", "# comment"}, nil, ""},
+		{
+			[]string{"allow Example.COM.", "  DENY  *.example.com ", "deny Martin+tag@Example.com", "allow *@example.net", "deny *"},
+			[]PolicyRule{
+				{Allow: true, Pattern: "example.com", Line: 1},
+				{Allow: false, Pattern: "*.example.com", Line: 2},
+				{Allow: false, Pattern: "martin@example.com", Line: 3},
+				{Allow: true, Pattern: "example.net", Line: 4},
+				{Allow: false, Pattern: "*", Line: 5},
+			},
+			"",
+		},
+		{[]string{"allow"}, nil, "invalid policy rule on line 1"},
+		{[]string{"allow a b"}, nil, "invalid policy rule on line 1"},
+		{[]string{"", "permit example.com"}, nil, `line 2: unknown action "permit"`},
+		{[]string{"allow invalid@"}, nil, "invalid address"},
+		{[]string{"allow foo.*.com"}, nil, "invalid wildcard"},
+		{[]string{"allow *example.com"}, nil, "invalid wildcard"},
+		{[]string{"allow *."}, nil, "invalid wildcard"},
+	}
+
+	for _, tc := range cases {
+		t.Run("", func(t *testing.T) {
+			p, err := CompilePolicy(tc.in...)
+			if !test.ErrorContains(err, tc.expectedErr) {
+				t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+			}
+			if tc.expectedErr != "" {
+				return
+			}
+			if len(p.Rules) != len(tc.expected) {
+				t.Fatalf("wrong rules: %v", p.Rules)
+			}
+			for i := range p.Rules {
+				if p.Rules[i] != tc.expected[i] {
+					t.Errorf("\nout:      %v\nexpected: %v\n", p.Rules[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	cases := []struct {
+		rules        []string
+		in           string
+		expected     bool
+		expectedRule string
+	}{
+		{nil, "martin@example.com", true, ""},
+		{nil, "invalid", false, ""},
+		{[]string{"allow example.com"}, "martin@example.com", true, "allow example.com"},
+		{[]string{"allow example.com"}, "martin@EXAMPLE.com", true, "allow example.com"},
+		{[]string{"allow example.com"}, "martin@example.net", false, ""},
+		{[]string{"deny example.com"}, "martin@example.net", true, ""},
+		{[]string{"allow *.contractor.com"}, "a@contractor.com", false, ""},
+		{[]string{"allow *.contractor.com"}, "a@x.contractor.com", true, "allow *.contractor.com"},
+		{[]string{"allow *.contractor.com"}, "a@xcontractor.com", false, ""},
+
+		// Most specific rule wins.
+		{[]string{"deny *", "allow example.com"}, "a@example.com", true, "allow example.com"},
+		{[]string{"allow example.com", "deny bad@example.com"}, "bad+tag@example.com", false, "deny bad@example.com"},
+		{[]string{"deny example.com", "allow good@example.com"}, "good@example.com", true, "allow good@example.com"},
+		{[]string{"allow *.com", "deny *.example.com"}, "a@b.example.com", false, "deny *.example.com"},
+		{[]string{"deny *.example.com", "allow *.b.example.com"}, "a@c.b.example.com", true, "allow *.b.example.com"},
+		{[]string{"deny *.example.com", "allow example.com"}, "a@b.example.com", false, "deny *.example.com"},
+
+		// Deny wins on a tie.
+		{[]string{"allow example.com", "deny example.com"}, "a@example.com", false, "deny example.com"},
+		{[]string{"deny example.com", "allow example.com"}, "a@example.com", false, "deny example.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			p, err := CompilePolicy(tc.rules...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := p.Check(Address{Address: tc.in})
+			if d.Allowed != tc.expected {
+				t.Errorf("wrong Allowed: %t", d.Allowed)
+			}
+
+			rule := ""
+			if d.Rule != nil {
+				rule = d.Rule.String()
+			}
+			if rule != tc.expectedRule {
+				t.Errorf("wrong rule\nout:      %v\nexpected: %v\n", rule, tc.expectedRule)
+			}
+		})
+	}
+}
+
+func TestPolicyPartition(t *testing.T) {
+	p, err := CompilePolicy("allow example.com", "allow *.contractor.com", "deny spam@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, _ := ParseList("a@example.com, spam@example.com, b@x.contractor.com, c@example.net, invalid")
+	allowed, rejected := p.Partition(l)
+	if allowed.String() != "a@example.com, b@x.contractor.com" {
+		t.Errorf("wrong allowed: %v", allowed)
+	}
+	if rejected.ValidAddresses().String() != "spam@example.com, c@example.net" || len(rejected) != 3 {
+		t.Errorf("wrong rejected: %#v", rejected)
+	}
+
+	d := p.Evaluate(l)
+	if len(d) != len(l) || d[1].Rule != &p.Rules[2] {
+		t.Errorf("wrong decisions: %#v", d)
+	}
+}
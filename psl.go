@@ -0,0 +1,172 @@
+package mailaddress
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed" // For the Public Suffix List.
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:embed data/public_suffix_list.dat
+var publicSuffixList []byte
+
+var (
+	defaultPSL     *PublicSuffixList
+	defaultPSLOnce sync.Once
+)
+
+// PublicSuffixList is a list of public suffixes, such as "com" or "co.uk",
+// from the format used by https://publicsuffix.org.
+//
+// A PublicSuffixList is safe for concurrent use, and the list can be replaced
+// at any time with Load().
+type PublicSuffixList struct {
+	mu        sync.RWMutex
+	rules     map[string]struct{}
+	wildcard  map[string]struct{}
+	exception map[string]struct{}
+}
+
+// PublicSuffixes gets the default Public Suffix List, which is used by
+// Address.RegistrableDomain() and friends. This is the list embedded in the
+// package, unless it was replaced with Load().
+func PublicSuffixes() *PublicSuffixList {
+	defaultPSLOnce.Do(func() {
+		defaultPSL, _ = LoadPublicSuffixList(bytes.NewReader(publicSuffixList))
+	})
+	return defaultPSL
+}
+
+// LoadPublicSuffixList creates a new list from r, which should be in the
+// public_suffix_list.dat format.
+func LoadPublicSuffixList(r io.Reader) (*PublicSuffixList, error) {
+	p := &PublicSuffixList{}
+	return p, p.Load(r)
+}
+
+// Load replaces the list with the rules from r, which should be in the
+// public_suffix_list.dat format.
+//
+// The list is only replaced if the entire list was read without errors.
+func (p *PublicSuffixList) Load(r io.Reader) error {
+	var (
+		rules     = make(map[string]struct{})
+		wildcard  = make(map[string]struct{})
+		exception = make(map[string]struct{})
+	)
+
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		// Rules end at the first whitespace.
+		line := strings.Fields(scan.Text())
+		if len(line) == 0 || strings.HasPrefix(line[0], "//") {
+			continue
+		}
+
+		// The list uses Unicode; store the IDNA-encoded form, which is what
+		// normalizeDomain() gives.
+		rule := strings.ToLower(line[0])
+		switch {
+		case strings.HasPrefix(rule, "!"):
+			exception[normalizeDomain(rule[1:])] = struct{}{}
+		case strings.HasPrefix(rule, "*."):
+			wildcard[normalizeDomain(rule[2:])] = struct{}{}
+		default:
+			rules[normalizeDomain(rule)] = struct{}{}
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.rules, p.wildcard, p.exception = rules, wildcard, exception
+	p.mu.Unlock()
+	return nil
+}
+
+// PublicSuffix gets the public suffix of the domain, e.g. "co.uk" for
+// "www.example.co.uk". Domains not matching any rule use the last label, as
+// per the "*" default rule.
+//
+// Both the Unicode and IDNA-encoded forms of a domain are matched, and the
+// suffix is returned in the same form as the domain; e.g. "公司.cn" for
+// "example.公司.cn" and "xn--55qx5d.cn" for "example.xn--55qx5d.cn".
+func (p *PublicSuffixList) PublicSuffix(domain string) string {
+	domain = lowerDomain(domain)
+	ascii := normalizeDomain(domain)
+	return sameForm(domain, ascii, p.publicSuffix(ascii))
+}
+
+func (p *PublicSuffixList) publicSuffix(domain string) string {
+	if domain == "" {
+		return ""
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Go from the longest possible suffix to the shortest; the first match is
+	// the longest one.
+	for s := domain; ; {
+		parent := ""
+		if dot := strings.IndexByte(s, '.'); dot > -1 {
+			parent = s[dot+1:]
+		}
+
+		if _, ok := p.exception[s]; ok {
+			return parent
+		}
+		if _, ok := p.rules[s]; ok {
+			return s
+		}
+		if _, ok := p.wildcard[parent]; ok && parent != "" {
+			return s
+		}
+
+		if parent == "" {
+			return s
+		}
+		s = parent
+	}
+}
+
+// RegistrableDomain gets the registrable domain (also known as "eTLD+1"), which
+// is the public suffix plus one label; e.g. "example.co.uk" for
+// "www.example.co.uk". This is an empty string if the domain is a public
+// suffix.
+//
+// The registrable domain is returned in the same form as the domain, as with
+// PublicSuffix().
+func (p *PublicSuffixList) RegistrableDomain(domain string) string {
+	domain = lowerDomain(domain)
+	ascii := normalizeDomain(domain)
+	suffix := p.publicSuffix(ascii)
+	if len(suffix) >= len(ascii) {
+		return ""
+	}
+
+	rest := ascii[:len(ascii)-len(suffix)-1]
+	if dot := strings.LastIndexByte(rest, '.'); dot > -1 {
+		rest = rest[dot+1:]
+	}
+	return sameForm(domain, ascii, rest+"."+suffix)
+}
+
+// sameForm gets the labels of domain that correspond to suffix, which is a
+// suffix of ascii (the IDNA-encoded form of domain).
+func sameForm(domain, ascii, suffix string) string {
+	if suffix == "" || domain == ascii {
+		return suffix
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) != strings.Count(ascii, ".")+1 {
+		// Different number of labels because of mapped dots, such as the
+		// ideographic full stop.
+		return suffix
+	}
+	return strings.Join(labels[len(labels)-strings.Count(suffix, ".")-1:], ".")
+}
@@ -0,0 +1,89 @@
+package mailaddress
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublicSuffixList(t *testing.T) {
+	cases := []struct {
+		in                          string
+		expectedSuffix, expectedReg string
+	}{
+		{"", "", ""},
+		{"com", "com", ""},
+		{"example.com", "com", "example.com"},
+		{"EXAMPLE.COM.", "com", "example.com"},
+		{"a.b.example.com", "com", "example.com"},
+		{"example.co.uk", "co.uk", "example.co.uk"},
+		{"support.example.co.uk", "co.uk", "example.co.uk"},
+		{"co.uk", "co.uk", ""},
+		{"example.unknowntld", "unknowntld", "example.unknowntld"},
+		{"foo.github.io", "github.io", "foo.github.io"},
+
+		// Wildcard and exception rules.
+		{"ck", "ck", ""},
+		{"foo.ck", "foo.ck", ""},
+		{"a.foo.ck", "foo.ck", "a.foo.ck"},
+		{"www.ck", "ck", "www.ck"},
+		{"a.www.ck", "ck", "www.ck"},
+
+		{"食狮.com.cn", "com.cn", "食狮.com.cn"},
+		{"www.食狮.中国", "中国", "食狮.中国"},
+		{"www.example.公司.cn", "公司.cn", "example.公司.cn"},
+
+		// IDNA-encoded domains.
+		{"www.example.xn--55qx5d.cn", "xn--55qx5d.cn", "example.xn--55qx5d.cn"},
+		{"WWW.EXAMPLE.XN--55QX5D.CN", "xn--55qx5d.cn", "example.xn--55qx5d.cn"},
+		{"www.xn--85x722f.xn--fiqs8s", "xn--fiqs8s", "xn--85x722f.xn--fiqs8s"},
+		{"xn--fiqs8s", "xn--fiqs8s", ""},
+		{"www.example。公司。cn", "xn--55qx5d.cn", "example.xn--55qx5d.cn"},
+	}
+
+	p := PublicSuffixes()
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			if out := p.PublicSuffix(tc.in); out != tc.expectedSuffix {
+				t.Errorf("PublicSuffix()\nout:      %v\nexpected: %v\n", out, tc.expectedSuffix)
+			}
+			if out := p.RegistrableDomain(tc.in); out != tc.expectedReg {
+				t.Errorf("RegistrableDomain()\nout:      %v\nexpected: %v\n", out, tc.expectedReg)
+			}
+		})
+	}
+}
+
+func TestPublicSuffixListLoad(t *testing.T) {
+	p, err := LoadPublicSuffixList(strings.NewReader(`
+// Comment
+example
+*.wild.example
+!not.wild.example
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"a.b.example":          "b.example",
+		"a.b.wild.example":     "a.b.wild.example",
+		"a.not.wild.example":   "not.wild.example",
+		"a.b.c.d.wild.example": "c.d.wild.example",
+		"example.com":          "example.com",
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			if out := p.RegistrableDomain(in); out != expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, expected)
+			}
+		})
+	}
+
+	err = p.Load(errReader{})
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if p.RegistrableDomain("a.b.example") != "b.example" {
+		t.Error("list replaced on error")
+	}
+}
@@ -0,0 +1,333 @@
+package mailaddress
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/teamwork/toutf8"
+)
+
+// maxEncodedWordLen is the maximum length of an encoded-word, from RFC 2047
+// section 2.
+const maxEncodedWordLen = 75
+
+var (
+	reEncodedWord = regexp.MustCompile(`=\?([^?\s]+)\?([bBqQ])\?([^?\s]*)\?=`)
+
+	// Also match encoded-words without the closing ?= for decodeLenient().
+	reLenientWord = regexp.MustCompile(`=\?([^?\s]+)\?([bBqQ])\?([^?\s]*)(\?=|\?|\s|$)`)
+)
+
+// ErrEncodedWord is used as a warning when an RFC 2047 encoded-word is
+// malformed and ParseOptions.Lenient is set; see Address.Warning().
+var ErrEncodedWord = errors.New("malformed encoded-word")
+
+// NameSegment is a part of the display name. Names with RFC 2047 encoding
+// consist of one segment for every encoded-word, and segments for the text in
+// between.
+type NameSegment struct {
+	// Text is the decoded text.
+	Text string
+
+	// Charset is the character set of the encoded-word as it appeared in the
+	// header, e.g. "ISO-2022-JP". This is empty if the text wasn't encoded.
+	Charset string
+
+	// Encoding is 'Q' or 'B', or 0 if the text wasn't encoded.
+	Encoding byte
+}
+
+// NameSegments gets the segments of the display name as it was before RFC 2047
+// decoding. This is nil if the name didn't contain any encoded-words.
+func (a Address) NameSegments() []NameSegment {
+	if a.encodedName == "" {
+		return nil
+	}
+	return decodeSegments(a.encodedName)
+}
+
+// decodeSegments splits the header text in encoded-words and the text in
+// between. Encoded-words which can't be decoded are treated as text.
+func decodeSegments(s string) []NameSegment {
+	var (
+		segs    []NameSegment
+		decoder = mime.WordDecoder{CharsetReader: toutf8.Reader}
+		prev    = 0
+		wasWord = false
+	)
+	for _, m := range reEncodedWord.FindAllStringSubmatchIndex(s, -1) {
+		text, err := decoder.Decode(s[m[0]:m[1]])
+		if err != nil {
+			continue
+		}
+
+		// Whitespace between two encoded-words is ignored (RFC 2047 section
+		// 6.2).
+		if between := s[prev:m[0]]; between != "" && !(wasWord && strings.TrimSpace(between) == "") {
+			segs = append(segs, NameSegment{Text: between})
+		}
+		segs = append(segs, NameSegment{
+			Text:     text,
+			Charset:  s[m[2]:m[3]],
+			Encoding: strings.ToUpper(s[m[4]:m[5]])[0],
+		})
+		prev, wasWord = m[1], true
+	}
+	if prev < len(s) {
+		segs = append(segs, NameSegment{Text: s[prev:]})
+	}
+	return segs
+}
+
+// decodeLenient decodes all RFC 2047 encoded-words in s, recovering from
+// malformed words where possible:
+//
+//   - words with an unknown character set are used as UTF-8 if they're valid
+//     UTF-8, and kept as-is otherwise;
+//   - words without the closing ?= are decoded up to the next whitespace;
+//   - words with invalid Q or B encoding are kept as-is.
+//
+// The returned error is a warning about the first malformed word.
+func decodeLenient(s string) (string, error) {
+	var (
+		b       strings.Builder
+		warning error
+		prev    = 0
+		wasWord = false
+	)
+	warn := func(format string, args ...interface{}) {
+		if warning == nil {
+			warning = fmt.Errorf("%w: "+format, append([]interface{}{ErrEncodedWord}, args...)...)
+		}
+	}
+
+	for _, m := range reLenientWord.FindAllStringSubmatchIndex(s, -1) {
+		end := m[1]
+		if term := s[m[8]:m[9]]; term != "?=" {
+			warn("no closing ?= in %q", s[m[0]:m[1]])
+			if term != "" && term != "?" {
+				end-- // Don't include the whitespace.
+			}
+		}
+		word := s[m[0]:end]
+
+		if between := s[prev:m[0]]; !(wasWord && strings.TrimSpace(between) == "") {
+			b.WriteString(between)
+		}
+		prev, wasWord = end, true
+
+		charset, enc, text := s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]]
+		raw, err := decodeWordText(enc, text)
+		if err != nil {
+			warn("%q: %s", word, err)
+			b.WriteString(word)
+			wasWord = false
+			continue
+		}
+
+		r, err := toutf8.Reader(charset, bytes.NewReader(raw))
+		if err == nil {
+			var dec []byte
+			dec, err = io.ReadAll(r)
+			if err == nil {
+				b.Write(dec)
+				continue
+			}
+		}
+
+		warn("%q: %s", word, err)
+		if utf8.Valid(raw) {
+			b.Write(raw)
+			continue
+		}
+		b.WriteString(word)
+		wasWord = false
+	}
+	b.WriteString(s[prev:])
+	return b.String(), warning
+}
+
+// decodeWordText decodes the text of an encoded-word with the Q or B encoding.
+func decodeWordText(enc, text string) ([]byte, error) {
+	if enc == "b" || enc == "B" {
+		b, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			// Some mailers leave out the padding.
+			b, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(text, "="))
+		}
+		return b, err
+	}
+
+	var b []byte
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '_':
+			b = append(b, ' ')
+		case c == '=' && i+2 < len(text) && isHex(text[i+1]) && isHex(text[i+2]):
+			n, _ := strconv.ParseUint(text[i+1:i+3], 16, 8)
+			b = append(b, byte(n))
+			i += 2
+		default:
+			// Invalid escapes and a trailing = are kept.
+			b = append(b, c)
+		}
+	}
+	return b, nil
+}
+
+// EncodeOptions are options for NameEncodedWith() and StringEncodedWith().
+type EncodeOptions struct {
+	// OriginalCharset encodes the name with the character set and encoding
+	// from the original encoded-words (see NameSegments()), rather than UTF-8
+	// and Q encoding.
+	//
+	// This is ignored if the name had no encoded-words, if the name was
+	// changed after parsing, or if the name can't be represented in the
+	// original character set.
+	OriginalCharset bool
+}
+
+// NameEncodedWith returns the name ready to be put in an email header, like
+// NameEncoded(), with the given options.
+func (a Address) NameEncodedWith(opts EncodeOptions) string {
+	if !opts.OriginalCharset {
+		return a.NameEncoded()
+	}
+
+	segs := a.NameSegments()
+	if segs == nil {
+		return a.NameEncoded()
+	}
+	var decoded strings.Builder
+	for _, s := range segs {
+		decoded.WriteString(s.Text)
+	}
+	if decoded.String() != a.Name {
+		return a.NameEncoded()
+	}
+
+	var (
+		b        strings.Builder
+		prevWord = false
+	)
+	for _, s := range segs {
+		if s.Encoding == 0 {
+			b.WriteString(encodeText(s.Text))
+			prevWord = false
+			continue
+		}
+
+		words, err := encodeWords(s)
+		if err != nil {
+			return a.NameEncoded()
+		}
+		if prevWord {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strings.Join(words, " "))
+		prevWord = true
+	}
+	return b.String()
+}
+
+// StringEncodedWith makes a string that is RFC 2047 encoded, like
+// StringEncoded(), with the given options.
+func (a Address) StringEncodedWith(opts EncodeOptions) string {
+	if a.Name == "" {
+		return a.Address
+	}
+	return fmt.Sprintf("%v <%v>", a.NameEncodedWith(opts), a.AddressEncoded())
+}
+
+// encodeText encodes text which wasn't in an encoded-word; it's quoted if it
+// contains special characters, and encoded as UTF-8 if it's not ASCII.
+func encodeText(text string) string {
+	if !isASCII(text) {
+		return mime.QEncoding.Encode("utf-8", text)
+	}
+
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || !strings.ContainsAny(trimmed, `",;@<>()`) {
+		return text
+	}
+
+	i := strings.Index(text, trimmed)
+	return text[:i] + `"` + strings.Replace(trimmed, `"`, `\"`, -1) + `"` + text[i+len(trimmed):]
+}
+
+// encodeWords encodes the segment in one or more encoded-words with the
+// segment's character set and encoding.
+func encodeWords(s NameSegment) ([]string, error) {
+	enc, ok := toutf8.FindEncoding(s.Charset)
+	if !ok {
+		return nil, toutf8.ErrUnknownCharset(fmt.Sprintf("unknown character set: %v", s.Charset))
+	}
+
+	// Split in multiple words if it's too long; every word needs to be
+	// complete characters, so encode every word on its own.
+	runes := []rune(s.Text)
+	if len(runes) == 0 {
+		return []string{encodeWord(s.Charset, s.Encoding, nil)}, nil
+	}
+
+	var (
+		words []string
+		start = 0
+		last  string
+	)
+	for i := 1; i <= len(runes); i++ {
+		b, err := enc.NewEncoder().Bytes([]byte(string(runes[start:i])))
+		if err != nil {
+			return nil, err
+		}
+
+		w := encodeWord(s.Charset, s.Encoding, b)
+		if len(w) > maxEncodedWordLen && i-start > 1 {
+			words = append(words, last)
+			start = i - 1
+			i--
+			continue
+		}
+		last = w
+	}
+	return append(words, last), nil
+}
+
+// encodeWord creates a single encoded-word.
+func encodeWord(charset string, encoding byte, b []byte) string {
+	var text string
+	if encoding == 'B' {
+		text = base64.StdEncoding.EncodeToString(b)
+	} else {
+		text = qEncode(b)
+	}
+	return "=?" + charset + "?" + string(encoding) + "?" + text + "?="
+}
+
+// qEncode applies the "Q" encoding. Only the characters allowed in a phrase
+// (RFC 2047 section 5) are not encoded.
+func qEncode(b []byte) string {
+	const hex = "0123456789ABCDEF"
+	var out strings.Builder
+	for _, c := range b {
+		switch {
+		case c == ' ':
+			out.WriteByte('_')
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.IndexByte("!*+-/", c) > -1:
+			out.WriteByte(c)
+		default:
+			out.WriteByte('=')
+			out.WriteByte(hex[c>>4])
+			out.WriteByte(hex[c&0x0f])
+		}
+	}
+	return out.String()
+}
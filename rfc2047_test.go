@@ -0,0 +1,246 @@
+package mailaddress
+
+import (
+	"errors"
+	"mime"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/test"
+	"github.com/teamwork/toutf8"
+)
+
+func TestNameSegments(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected []NameSegment
+	}{
+		{"Martin <martin@example.com>", nil},
+		{"martin@example.com", nil},
+		{"=?utf-8?q?martin@example.com?=", nil},
+		{"=?ISO-2022-JP?B?GyRCJUYlOSVIGyhC?= <a@example.com>", []NameSegment{
+			{Text: "テスト", Charset: "ISO-2022-JP", Encoding: 'B'},
+		}},
+		{"=?utf-8?q?Mart=C3=AFn?= Tournoij <a@example.com>", []NameSegment{
+			{Text: "Martïn", Charset: "utf-8", Encoding: 'Q'},
+			{Text: " Tournoij"},
+		}},
+		{"=?utf-8?q?Mart=C3=AFn?=  =?iso-8859-1?q?Andr=E9?= <a@example.com>", []NameSegment{
+			{Text: "Martïn", Charset: "utf-8", Encoding: 'Q'},
+			{Text: "André", Charset: "iso-8859-1", Encoding: 'Q'},
+		}},
+		{`"Smith, =?iso-8859-1?q?Andr=E9?=" <a@example.com>`, []NameSegment{
+			{Text: "Smith, "},
+			{Text: "André", Charset: "iso-8859-1", Encoding: 'Q'},
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			l, _ := ParseList(tc.in)
+			if len(l) != 1 {
+				t.Fatalf("len %d", len(l))
+			}
+			out := l[0].NameSegments()
+			if !reflect.DeepEqual(out, tc.expected) {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNameEncodedWith(t *testing.T) {
+	long := strings.Repeat("テスト", 20)
+	enc, _ := toutf8.FindEncoding("ISO-2022-JP")
+	longJP, _ := enc.NewEncoder().String(long)
+
+	cases := []struct {
+		in       string
+		change   string
+		expected string
+	}{
+		{"Martin <martin@example.com>", "", "Martin"},
+		{"Martïn <martin@example.com>", "", "=?utf-8?q?Mart=C3=AFn?="},
+		{"=?ISO-2022-JP?B?GyRCJUYlOSVIGyhC?= <a@example.com>", "", "=?ISO-2022-JP?B?GyRCJUYlOSVIGyhC?="},
+		{"=?iso-8859-1?q?Andr=E9?= Smith <a@example.com>", "", "=?iso-8859-1?Q?Andr=E9?= Smith"},
+		{"=?iso-8859-1?b?QW5kcuk=?=  =?utf-8?q?Mart=C3=AFn?= <a@example.com>", "", "=?iso-8859-1?B?QW5kcuk=?= =?utf-8?Q?Mart=C3=AFn?="},
+		{`"Smith, =?iso-8859-1?q?Andr=E9?=" <a@example.com>`, "", `"Smith," =?iso-8859-1?Q?Andr=E9?=`},
+
+		// Falls back to UTF-8 if the name was changed.
+		{"=?iso-8859-1?q?Andr=E9?= <a@example.com>", "Andrè", "=?utf-8?q?Andr=C3=A8?="},
+		{"=?iso-8859-1?q?Andr=E9?= <a@example.com>", "", "=?iso-8859-1?Q?Andr=E9?="},
+		{"=?us-ascii?q?Andre?= <a@example.com>", "", "=?us-ascii?Q?Andre?="},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			a, err := Parse(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.change != "" {
+				a.Name = tc.change
+			}
+
+			out := a.NameEncodedWith(EncodeOptions{OriginalCharset: true})
+			if out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+			if def := a.NameEncodedWith(EncodeOptions{}); def != a.NameEncoded() {
+				t.Errorf("different without options: %v", def)
+			}
+		})
+	}
+
+	t.Run("long", func(t *testing.T) {
+		a, err := Parse(mime.BEncoding.Encode("ISO-2022-JP", longJP) + " <a@example.com>")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Name != long {
+			t.Fatalf("wrong name: %q", a.Name)
+		}
+
+		out := a.StringEncodedWith(EncodeOptions{OriginalCharset: true})
+		words := strings.Fields(strings.TrimSuffix(out, " <a@example.com>"))
+		if len(words) < 2 {
+			t.Fatalf("not split: %s", out)
+		}
+		for _, w := range words {
+			if len(w) > maxEncodedWordLen || !strings.HasPrefix(w, "=?ISO-2022-JP?B?") {
+				t.Errorf("wrong word: %q", w)
+			}
+		}
+
+		back, err := Parse(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back.Name != long {
+			t.Errorf("\nout:      %v\nexpected: %v\n", back.Name, long)
+		}
+	})
+}
+
+func TestDecodeLenient(t *testing.T) {
+	cases := []struct {
+		in, expected, wantWarn string
+	}{
+		{"", "", ""},
+		{"Martin", "Martin", ""},
+		{"=?utf-8?q?B=C3=B6b?=", "Böb", ""},
+		{"=?utf-8?q?B=C3=B6b?= =?utf-8?q?_Jac=C3=B6b?=", "Böb Jacöb", ""},
+		{"=?utf-8?b?QsO2YiwgSmFjw7Zi?=", "Böb, Jacöb", ""},
+		{"=?iso-8859-1?q?Andr=E9?= Smith", "André Smith", ""},
+
+		// Unknown charset; use if it's valid UTF-8.
+		{"=?x-unknown?q?B=C3=B6b?=", "Böb", `malformed encoded-word: "=?x-unknown?q?B=C3=B6b?=": unknown character set: x-unknown`},
+		{"=?x-unknown?q?Andr=E9?= Smith", "=?x-unknown?q?Andr=E9?= Smith", "unknown character set"},
+		{"=?GB2312?B?us6V08qk?=", "=?GB2312?B?us6V08qk?=", "unknown character set"},
+
+		// No closing ?=
+		{"=?utf-8?q?B=C3=B6b", "Böb", `malformed encoded-word: no closing ?= in "=?utf-8?q?B=C3=B6b"`},
+		{"=?utf-8?q?B=C3=B6b?", "Böb", "no closing ?="},
+		{"=?utf-8?q?B=C3=B6b Smith", "Böb Smith", "no closing ?="},
+		{"=?utf-8?b?QsO2Yg Smith", "Böb Smith", "no closing ?="},
+
+		// Invalid encoding.
+		{"=?utf-8?q?B=C3=B6b=?=", "Böb=", ""},
+		{"=?utf-8?q?B=ZZb?=", "B=ZZb", ""},
+		{"=?utf-8?b?!!!?= Smith", "=?utf-8?b?!!!?= Smith", "illegal base64 data"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out, warn := decodeLenient(tc.in)
+			if !test.ErrorContains(warn, tc.wantWarn) {
+				t.Errorf("wrong warning: %v", warn)
+			}
+			if warn != nil && !errors.Is(warn, ErrEncodedWord) {
+				t.Errorf("not ErrEncodedWord: %v", warn)
+			}
+			if out != tc.expected {
+				t.Errorf("\nout:      %q\nexpected: %q\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseLenientList(t *testing.T) {
+	in := "=?utf-8?q?B=C3=B6b,_Jac=C3=B6b?= <bob@example.com>, =?utf-8?q?x,y <x@example.com>, z@example.com"
+	expected := List{
+		{Name: "Böb, Jacöb", Address: "bob@example.com"},
+		{Name: "x,y", Address: "x@example.com"},
+		{Address: "z@example.com"},
+	}
+
+	l, haveErr := ParseListWithOptions(in, ParseOptions{Lenient: true})
+	if haveErr {
+		t.Fatal(l.Errors())
+	}
+	if !cmplist(l, expected) {
+		t.Errorf("\nout:      %v\nexpected: %v\n", l, expected)
+	}
+
+	s := NewScanner(strings.NewReader(in))
+	s.Options = ParseOptions{Lenient: true}
+	var scanned List
+	for s.Scan() {
+		scanned = append(scanned, s.Address())
+	}
+	if !cmplist(scanned, expected) {
+		t.Errorf("Scanner\nout:      %v\nexpected: %v\n", scanned, expected)
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected Address
+		wantErr  string
+		wantWarn string
+	}{
+		{"=?utf-8?q?B=C3=B6b?= <bob@example.com>", Address{Name: "Böb", Address: "bob@example.com"}, "", ""},
+		{`"=?utf-8?q?B=C3=B6b,?= =?utf-8?q?_Jac=C3=B6b?=" <bob@example.com>`, Address{Name: "Böb, Jacöb", Address: "bob@example.com"}, "", ""},
+		{"=?x-unknown?q?B=C3=B6b?= <bob@example.com>", Address{Name: "Böb", Address: "bob@example.com"}, "", "unknown character set"},
+		{"=?GB2312?B?us6V08qk?= <secmocu@jshjkj.com>", Address{Name: "=?GB2312?B?us6V08qk?=", Address: "secmocu@jshjkj.com"}, "", "unknown character set"},
+		{"=?utf-8?q?B=C3=B6b <bob@example.com>", Address{Name: "Böb", Address: "bob@example.com"}, "", "no closing ?="},
+		{"=?utf-8?q?B=C3=B6b,_Jac=C3=B6b?= <bob@example.com>", Address{Name: "Böb, Jacöb", Address: "bob@example.com"},
+			"", `unencoded ',' in encoded-word`},
+		{"=?utf-8?q?<B=C3=B6b>;?= <bob@example.com>", Address{Name: "<Böb>;", Address: "bob@example.com"},
+			"", `unencoded '<' in encoded-word`},
+
+		// Still an error if the address is invalid.
+		{"=?x-unknown?q?B=C3=B6b?= <bob>", Address{Name: "Böb", Address: "bob"}, "unable to find", "unknown character set"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			// Without lenient it's either the same or an error.
+			strict, _ := ParseList(tc.in)
+			if tc.wantWarn != "" && strict[0].Error() == nil && strict[0].Name == tc.expected.Name {
+				t.Errorf("no difference without Lenient: %#v", strict[0])
+			}
+
+			l, _ := ParseListWithOptions(tc.in, ParseOptions{Lenient: true})
+			if len(l) != 1 {
+				t.Fatalf("len %d", len(l))
+			}
+			a := l[0]
+
+			if !test.ErrorContains(a.Error(), tc.wantErr) {
+				t.Errorf("wrong error: %v", a.Error())
+			}
+			if !test.ErrorContains(a.Warning(), tc.wantWarn) {
+				t.Errorf("wrong warning: %v", a.Warning())
+			}
+			if !test.ErrorContains(l.Warnings(), tc.wantWarn) {
+				t.Errorf("wrong warnings: %v", l.Warnings())
+			}
+			if !cmpaddr(a, tc.expected) {
+				t.Errorf("\nout:      %v\nexpected: %v\n", fmtaddr(a), fmtaddr(tc.expected))
+			}
+		})
+	}
+}
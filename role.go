@@ -0,0 +1,93 @@
+package mailaddress
+
+import (
+	"path"
+	"strings"
+)
+
+// Class is the classification of an address.
+type Class int8
+
+// Address classes.
+const (
+	Personal  Class = iota // A person.
+	Role                   // Role account, such as info@ or support@.
+	NoReply                // No-reply address, such as noreply@.
+	Automated              // Automated sender, such as mailer-daemon@.
+)
+
+func (c Class) String() string {
+	switch c {
+	case Personal:
+		return "personal"
+	case Role:
+		return "role"
+	case NoReply:
+		return "no-reply"
+	case Automated:
+		return "automated"
+	default:
+		return "unknown"
+	}
+}
+
+// RoleSet is a set of patterns to classify addresses. The patterns are matched
+// against the lower-cased local part without the tag, and may contain
+// wildcards as accepted by path.Match (e.g. "no-reply*").
+type RoleSet struct {
+	Role      []string
+	NoReply   []string
+	Automated []string
+}
+
+// Roles is the RoleSet used by Address.IsRole(), Address.IsNoReply(), and
+// List.Classify(). It's safe to modify this, but not concurrently with using
+// it.
+var Roles = &RoleSet{
+	// Mostly from RFC 2142, and other common ones.
+	Role: []string{
+		"abuse", "accounts", "admin", "administrator", "billing", "careers",
+		"contact", "enquiries", "help", "helpdesk", "hostmaster", "hr", "info",
+		"jobs", "marketing", "media", "noc", "office", "orders", "postmaster",
+		"press", "privacy", "root", "sales", "security", "service", "support",
+		"team", "usenet", "uucp", "webmaster", "www",
+	},
+	NoReply: []string{
+		"noreply*", "no-reply*", "no_reply*", "donotreply*", "do-not-reply*",
+		"do_not_reply*", "*-noreply", "*-no-reply", "*.noreply", "*_noreply",
+	},
+	Automated: []string{
+		"mailer-daemon", "maildaemon", "mail-daemon", "daemon", "bounce*",
+		"*-bounces", "*-bounce", "notification*", "notify", "alert*", "cron",
+		"listserv", "majordomo", "*-owner", "owner-*", "*-request",
+	},
+}
+
+// Classify an address. No-reply patterns are checked first, then automated,
+// and then role patterns.
+func (r *RoleSet) Classify(a Address) Class {
+	local := strings.ToLower(a.Local())
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+
+	switch {
+	case matchAny(r.NoReply, local):
+		return NoReply
+	case matchAny(r.Automated, local):
+		return Automated
+	case matchAny(r.Role, local):
+		return Role
+	default:
+		return Personal
+	}
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
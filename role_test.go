@@ -0,0 +1,55 @@
+package mailaddress
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected Class
+	}{
+		{"", Personal},
+		{"martin@example.com", Personal},
+		{"info@example.com", Role},
+		{"Support+ticket@example.com", Role},
+		{"POSTMASTER@example.com", Role},
+		{"infomartin@example.com", Personal},
+		{"noreply@example.com", NoReply},
+		{"no-reply-123@example.com", NoReply},
+		{"DoNotReply@example.com", NoReply},
+		{"billing-noreply@example.com", NoReply},
+		{"mailer-daemon@example.com", Automated},
+		{"bounces+abc@example.com", Automated},
+		{"list-bounces@example.com", Automated},
+		{"notifications@github.com", Automated},
+		{"owner-list@example.com", Automated},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out := Roles.Classify(Address{Address: tc.in})
+			if out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRoleSet(t *testing.T) {
+	r := &RoleSet{Role: []string{"team-*"}, NoReply: []string{"[bad"}}
+	if c := r.Classify(Address{Address: "team-a@example.com"}); c != Role {
+		t.Errorf("wrong class: %v", c)
+	}
+	if c := r.Classify(Address{Address: "info@example.com"}); c != Personal {
+		t.Errorf("wrong class: %v", c)
+	}
+}
+
+func TestClassString(t *testing.T) {
+	for c, expected := range map[Class]string{
+		Personal: "personal", Role: "role", NoReply: "no-reply", Automated: "automated", 42: "unknown",
+	} {
+		if c.String() != expected {
+			t.Errorf("\nout:      %v\nexpected: %v\n", c.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package mailaddress
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// "John via Teamwork", "John (via Teamwork)".
+	reNameVia = regexp.MustCompile(`(?i)[\s(\[]+via\s+.*$`)
+
+	// Brackets with nothing meaningful in them, e.g. left over after removing
+	// the address in "John (john@example.com)".
+	reNameEmptyBrackets = regexp.MustCompile(
+		`\([^\p{L}\p{N}]*\)|\[[^\p{L}\p{N}]*\]|<[^\p{L}\p{N}]*>|"[^\p{L}\p{N}]*"|'[^\p{L}\p{N}]*'`)
+)
+
+// Pairs of characters that may wrap a name.
+var nameWrappers = []string{`""`, `''`, "()", "[]", "<>", "“”", "‘’"}
+
+// SanitizeName cleans up junk that is often seen in display names from
+// inbound mail. It removes:
+//
+//   - copies of the address, or any other address;
+//   - "via" suffixes, as added by mailing lists and applications (e.g. "John
+//     via Teamwork");
+//   - quotes and brackets around the entire name;
+//   - excessive whitespace.
+//
+// An empty string is returned if nothing meaningful remains.
+func SanitizeName(name, address string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if address != "" {
+		name = removeAddress(name, address)
+	}
+	name = reFindEmail.ReplaceAllString(name, "")
+	name = reNameVia.ReplaceAllString(name, "")
+
+	for {
+		prev := name
+		name = reNameEmptyBrackets.ReplaceAllString(name, "")
+		name = strings.Trim(name, " \t-,;:|")
+		name = unwrapName(name)
+		if name == prev {
+			break
+		}
+	}
+
+	name = strings.Join(strings.Fields(name), " ")
+	if strings.IndexFunc(name, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) == -1 {
+		return ""
+	}
+	return name
+}
+
+// unwrapName removes quotes or brackets around the entire name.
+func unwrapName(name string) string {
+	for _, w := range nameWrappers {
+		l, r := w[:len(w)/2], w[len(w)/2:]
+		if len(name) >= len(w) && strings.HasPrefix(name, l) && strings.HasSuffix(name, r) &&
+			!strings.Contains(name[len(l):len(name)-len(r)], r) {
+			return strings.TrimSpace(name[len(l) : len(name)-len(r)])
+		}
+	}
+	return name
+}
+
+// removeAddress removes all case-insensitive occurrences of address in name,
+// including a "mailto:" prefix.
+func removeAddress(name, address string) string {
+	re := regexp.MustCompile(`(?i)(mailto:)?` + regexp.QuoteMeta(address))
+	return re.ReplaceAllLiteralString(name, "")
+}
@@ -0,0 +1,101 @@
+package mailaddress
+
+import "testing"
+
+func TestSanitizeName(t *testing.T) {
+	cases := []struct {
+		name, address, expected string
+	}{
+		{"", "", ""},
+		{"John Doe", "john@x.com", "John Doe"},
+		{"  John \t  Doe ", "john@x.com", "John Doe"},
+		{"Doe, John", "john@x.com", "Doe, John"},
+		{"Olivia Smith", "o@x.com", "Olivia Smith"},
+		{"Conan O'Brien", "c@x.com", "Conan O'Brien"},
+		{"Rock 'n' Roll", "r@x.com", "Rock 'n' Roll"},
+
+		// Copies of the address.
+		{"john@x.com", "john@x.com", ""},
+		{"JOHN@X.COM", "john@x.com", ""},
+		{"'john@x.com'", "john@x.com", ""},
+		{"John Doe (john@x.com)", "john@x.com", "John Doe"},
+		{`"John Doe (john@x.com)"`, "john@x.com", "John Doe"},
+		{"John Doe <john@x.com>", "john@x.com", "John Doe"},
+		{"John Doe [mailto:john@x.com]", "john@x.com", "John Doe"},
+		{"John Doe - other@example.com", "john@x.com", "John Doe"},
+
+		// via
+		{"'john@x.com' via Teamwork", "notifications@teamwork.com", ""},
+		{"John Doe via Teamwork", "notifications@teamwork.com", "John Doe"},
+		{"John Doe (via Google Docs)", "drive@google.com", "John Doe"},
+		{"John Doe VIA list", "list@example.com", "John Doe"},
+
+		// Wrapping.
+		{`"John Doe"`, "john@x.com", "John Doe"},
+		{`'"John Doe"'`, "john@x.com", "John Doe"},
+		{"(John Doe)", "john@x.com", "John Doe"},
+		{"[John Doe]", "john@x.com", "John Doe"},
+		{"“John Doe”", "john@x.com", "John Doe"},
+		{"(John) (Doe)", "john@x.com", "(John) (Doe)"},
+		{"(Ünïcode)", "john@x.com", "Ünïcode"},
+
+		// Nothing left.
+		{`""`, "john@x.com", ""},
+		{"-- ()", "john@x.com", ""},
+		{"...", "john@x.com", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := SanitizeName(tc.name, tc.address)
+			if out != tc.expected {
+				t.Errorf("\nout:      %q\nexpected: %q\n", out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseSanitizeName(t *testing.T) {
+	cases := []struct {
+		in       string
+		opts     ParseOptions
+		expected List
+	}{
+		{
+			`"'john@x.com' via Teamwork" <notifications@teamwork.com>, "John Doe (john@x.com)" <john@x.com>`,
+			ParseOptions{},
+			List{
+				{Name: "'john@x.com' via Teamwork", Address: "notifications@teamwork.com"},
+				{Name: "John Doe (john@x.com)", Address: "john@x.com"},
+			},
+		},
+		{
+			`"'john@x.com' via Teamwork" <notifications@teamwork.com>, "John Doe (john@x.com)" <john@x.com>`,
+			ParseOptions{SanitizeName: true},
+			List{
+				{Address: "notifications@teamwork.com"},
+				{Name: "John Doe", Address: "john@x.com"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out, haveErr := ParseListWithOptions(tc.in, tc.opts)
+			if haveErr {
+				t.Fatal(out.Errors())
+			}
+			if !cmplist(out, tc.expected) {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, tc.expected)
+			}
+		})
+	}
+
+	a, err := ParseWithOptions(`"  Martin   Tournoij " <martin@example.com>`, ParseOptions{SanitizeName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Name != "Martin Tournoij" {
+		t.Errorf("wrong name: %q", a.Name)
+	}
+}
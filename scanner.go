@@ -0,0 +1,127 @@
+package mailaddress
+
+import (
+	"bufio"
+	"io"
+)
+
+// Scanner reads addresses from a list one at a time, for lists which are too
+// large to parse with ParseList(). The input is parsed the same as
+// ParseList(), except that duplicates are not removed.
+//
+// Only the address that is currently being parsed is kept in memory, so
+// memory usage depends on the size of the largest address rather than the size
+// of the list.
+//
+//	s := mailaddress.NewScanner(r)
+//	for s.Scan() {
+//	    addr := s.Address()
+//	    if err := addr.Error(); err != nil {
+//	        // Handle invalid address.
+//	    }
+//	}
+//	if err := s.Err(); err != nil {
+//	    // Handle read error.
+//	}
+type Scanner struct {
+	// Options for parsing the addresses; this should be set before the first
+	// call to Scan().
+	Options ParseOptions
+
+	r    *bufio.Reader
+	p    parser
+	addr Address
+	err  error
+	done bool
+}
+
+// NewScanner creates a new Scanner to read addresses from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{r: bufio.NewReader(&stickyReader{r: r})}
+}
+
+// Scan advances to the next address, which will be available with Address().
+// It returns false when there are no more addresses or on a read error.
+//
+// Invalid addresses are returned as well; use Address().Error() to check if
+// the address is valid.
+func (s *Scanner) Scan() bool {
+	s.p.opts = s.Options
+	for !s.done {
+		code, err := s.readRune()
+		if err != nil {
+			s.done = true
+			if err != io.EOF {
+				s.err = err
+				return false
+			}
+
+			var ok bool
+			s.addr, ok = s.p.end()
+			return ok
+		}
+
+		if addr, ok := s.p.next(code, s.more()); ok {
+			s.addr = addr
+			return true
+		}
+	}
+	return false
+}
+
+// Address gets the most recent address read by Scan().
+func (s *Scanner) Address() Address {
+	return s.addr
+}
+
+// Err returns the first non-EOF error that was encountered while reading.
+// Errors in the addresses are not reported here, but on the Address.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// readRune reads the next character; whitespace is collapsed to a single space
+// in the same way parse() does.
+func (s *Scanner) readRune() (rune, error) {
+	code, _, err := s.r.ReadRune()
+	if err != nil || !isSpace(code) {
+		return code, err
+	}
+
+	for {
+		code, _, err := s.r.ReadRune()
+		if err != nil {
+			return ' ', nil
+		}
+		if !isSpace(code) {
+			return ' ', s.r.UnreadRune()
+		}
+	}
+}
+
+// more reports if there is any more input.
+func (s *Scanner) more() bool {
+	_, err := s.r.Peek(1)
+	return err == nil
+}
+
+// stickyReader keeps returning the first error from r; bufio.Reader only
+// returns an error once, and we sometimes read ahead with Peek().
+type stickyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *stickyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	var n int
+	n, r.err = r.r.Read(p)
+	return n, r.err
+}
+
+// isSpace reports if c is matched by \s in regexp.
+func isSpace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
@@ -0,0 +1,104 @@
+package mailaddress
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/teamwork/test"
+)
+
+func scanAll(r io.Reader) (List, error) {
+	l := List{}
+	s := NewScanner(r)
+	for s.Scan() {
+		l = append(l, s.Address())
+	}
+	return l, s.Err()
+}
+
+func TestScanner(t *testing.T) {
+	cases := []string{
+		"",
+		"   ",
+		"martin@example.com",
+		"martin@example.com, foo@example.com;bar@example.com",
+		"martin@example.com,\r\n\tfoo@example.com",
+		`"Martin, Tournoij" <martin@example.com>, "Foo \"bar\"" <foo@example.com>`,
+		"=?utf-8?q?Mart=C3=AFn?= <martin@example.com>, =?ISO-8859-1?Q?Andr=E9?= <andre@example.com>",
+		"<a@example.com b@example.com>",
+		"Martin <martin@example.com> trailing, foo@example.com",
+		"duplicate@example.com, duplicate@example.com",
+		"invalid, no.at.example.com, martin@example.com",
+		"a <b <c@example.com>",
+		"\xff@example.com, martin@example.com",
+		"trailing@example.com, ",
+		"Martin <martin@example.com>",
+		"Martin <martin@example.com> ",
+	}
+	for k := range validAddresses {
+		cases = append(cases, k)
+	}
+	for _, k := range invalidAddresses {
+		cases = append(cases, k)
+	}
+
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			expected, _ := parse(tc, ParseOptions{})
+
+			// One byte at a time, so that everything spans the buffer
+			// boundaries.
+			out, err := scanAll(iotest.OneByteReader(strings.NewReader(tc)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(out, expected) {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", out, expected)
+			}
+		})
+	}
+}
+
+func TestScannerLarge(t *testing.T) {
+	const n = 50000
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\"User %[1]d\" <user%[1]d@example.com>,\n", i)
+	}
+
+	s := NewScanner(strings.NewReader(b.String()))
+	i := 0
+	for ; s.Scan(); i++ {
+		a := s.Address()
+		if want := fmt.Sprintf("user%d@example.com", i); a.Address != want || a.Error() != nil {
+			t.Fatalf("%d: %#v", i, a)
+		}
+	}
+	if s.Err() != nil {
+		t.Fatal(s.Err())
+	}
+	if i != n {
+		t.Errorf("read %d addresses; want %d", i, n)
+	}
+}
+
+func TestScannerErr(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("a@example.com, b@exa"), errReader{})
+	out, err := scanAll(r)
+	if !test.ErrorContains(err, "oh noes") {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if len(out) != 1 || out[0].Address != "a@example.com" {
+		t.Errorf("wrong output: %#v", out)
+	}
+
+	_, err = scanAll(iotest.TimeoutReader(strings.NewReader("a@example.com")))
+	if !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("wrong error: %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package mailaddress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidPath is used when an SMTP reverse-path or forward-path can't
+	// be parsed.
+	ErrInvalidPath = errors.New("invalid SMTP path")
+
+	// ErrInvalidCommand is used when a line is not a MAIL or RCPT command.
+	ErrInvalidCommand = errors.New("not a MAIL FROM or RCPT TO command")
+
+	// ErrInvalidParam is used when an ESMTP parameter is malformed.
+	ErrInvalidParam = errors.New("invalid ESMTP parameter")
+
+	// ErrInvalidXtext is used when xtext (RFC 3461) can't be decoded.
+	ErrInvalidXtext = errors.New("invalid xtext encoding")
+)
+
+// Path is an SMTP reverse-path (MAIL FROM) or forward-path (RCPT TO), as
+// described in RFC 5321, section 4.1.2.
+type Path struct {
+	// Address is the mailbox; this is the zero value for the null path.
+	Address Address
+
+	// Route is the (deprecated) source route, without the leading @; e.g.
+	// <@a.example,@b.example:user@c.example> has the route
+	// ["a.example", "b.example"]. Most servers will ignore this.
+	Route []string
+
+	// Null is set for the null reverse-path: <>
+	Null bool
+}
+
+// IsPostmaster reports if this is the special <Postmaster> path, which is the
+// local postmaster without a domain.
+func (p Path) IsPostmaster() bool {
+	return strings.EqualFold(p.Address.Address, "postmaster")
+}
+
+// String formats the path as it would appear in an SMTP command.
+func (p Path) String() string {
+	if p.Null {
+		return "<>"
+	}
+
+	route := ""
+	if len(p.Route) > 0 {
+		route = "@" + strings.Join(p.Route, ",@") + ":"
+	}
+	return "<" + route + p.Address.Address + ">"
+}
+
+// SMTPCommand is a parsed MAIL FROM or RCPT TO command.
+type SMTPCommand struct {
+	// Verb is "MAIL" or "RCPT".
+	Verb string
+
+	Path Path
+
+	// Params are the ESMTP parameters; the keys are upper-cased, and
+	// parameters without a value (e.g. SMTPUTF8) have an empty value. The
+	// xtext encoding of ORCPT and ENVID is removed.
+	Params map[string]string
+}
+
+// OriginalRecipient gets the address type and address from the ORCPT
+// parameter (RFC 3461), e.g. "rfc822" and "user@example.com".
+func (c SMTPCommand) OriginalRecipient() (addrType, addr string) {
+	orcpt, ok := c.Params["ORCPT"]
+	if !ok {
+		return "", ""
+	}
+
+	i := strings.Index(orcpt, ";")
+	if i == -1 {
+		return "", orcpt
+	}
+	return orcpt[:i], orcpt[i+1:]
+}
+
+// ParseSMTPCommand parses an SMTP MAIL or RCPT command, for example:
+//
+//	MAIL FROM:<user@example.com> SIZE=1234 BODY=8BITMIME SMTPUTF8
+//	RCPT TO:<x@example.com> NOTIFY=SUCCESS ORCPT=rfc822;x@example.com
+//
+// The verb is case-insensitive, and whitespace after the colon is allowed
+// since many clients send it.
+func ParseSMTPCommand(line string) (SMTPCommand, error) {
+	line = strings.TrimRight(line, "\r\n")
+
+	var cmd SMTPCommand
+	upper := strings.ToUpper(line)
+	switch {
+	case strings.HasPrefix(upper, "MAIL FROM:"):
+		cmd.Verb = "MAIL"
+		line = line[10:]
+	case strings.HasPrefix(upper, "RCPT TO:"):
+		cmd.Verb = "RCPT"
+		line = line[8:]
+	default:
+		return cmd, ErrInvalidCommand
+	}
+
+	path, rest, err := splitPath(strings.TrimLeft(line, " "))
+	if err != nil {
+		return cmd, err
+	}
+
+	cmd.Path, err = ParsePath(path)
+	if err != nil {
+		return cmd, err
+	}
+
+	switch {
+	case cmd.Verb == "RCPT" && cmd.Path.Null:
+		return cmd, fmt.Errorf("%w: null path not allowed in RCPT", ErrInvalidPath)
+	case cmd.Verb == "MAIL" && cmd.Path.IsPostmaster():
+		return cmd, fmt.Errorf("%w: <Postmaster> not allowed in MAIL", ErrInvalidPath)
+	}
+
+	cmd.Params, err = parseParams(rest)
+	return cmd, err
+}
+
+// ParsePath parses a single SMTP path, such as <user@example.com>, the null
+// path <>, or <Postmaster>. The angle brackets are optional, except for the null
+// path.
+func ParsePath(str string) (Path, error) {
+	var p Path
+
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return p, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+	if strings.HasPrefix(str, "<") {
+		if !strings.HasSuffix(str, ">") {
+			return p, fmt.Errorf("%w: no closing >", ErrInvalidPath)
+		}
+		str = str[1 : len(str)-1]
+
+		// Only the literal <> is the null path.
+		if str == "" {
+			p.Null = true
+			return p, nil
+		}
+	}
+
+	if strings.HasPrefix(str, "@") {
+		colon := strings.Index(str, ":")
+		if colon == -1 {
+			return p, fmt.Errorf("%w: source route without :", ErrInvalidPath)
+		}
+
+		for _, r := range strings.Split(str[:colon], ",") {
+			if len(r) < 2 || r[0] != '@' {
+				return p, fmt.Errorf("%w: invalid source route %q", ErrInvalidPath, r)
+			}
+			p.Route = append(p.Route, r[1:])
+		}
+		str = str[colon+1:]
+	}
+
+	at := lastUnquoted(str, '@')
+	switch {
+	case at == -1 && strings.EqualFold(str, "postmaster"):
+		// Special case from RFC 5321, section 4.5.1.
+	case at == -1:
+		return p, fmt.Errorf("%w: no domain in %q", ErrInvalidPath, str)
+	case at == 0 || at == len(str)-1:
+		return p, fmt.Errorf("%w: empty local part or domain in %q", ErrInvalidPath, str)
+	case strings.ContainsAny(str[at+1:], " <>"):
+		return p, fmt.Errorf("%w: invalid domain in %q", ErrInvalidPath, str)
+	}
+
+	p.Address = Address{Address: str, Raw: str}
+	return p, nil
+}
+
+// splitPath splits the path from the ESMTP parameters following it.
+func splitPath(str string) (path, rest string, err error) {
+	if !strings.HasPrefix(str, "<") {
+		// Be lenient and allow a bare address.
+		i := strings.Index(str, " ")
+		if i == -1 {
+			return str, "", nil
+		}
+		return str[:i], str[i+1:], nil
+	}
+
+	inQuote := false
+	for i := 1; i < len(str); i++ {
+		switch {
+		case str[i] == '\\' && inQuote:
+			i++
+		case str[i] == '"':
+			inQuote = !inQuote
+		case str[i] == '>' && !inQuote:
+			return str[:i+1], str[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: no closing >", ErrInvalidPath)
+}
+
+// lastUnquoted gets the index of the last c which is not in a quoted string.
+func lastUnquoted(str string, c byte) int {
+	idx := -1
+	inQuote := false
+	for i := 0; i < len(str); i++ {
+		switch {
+		case str[i] == '\\' && inQuote:
+			i++
+		case str[i] == '"':
+			inQuote = !inQuote
+		case str[i] == c && !inQuote:
+			idx = i
+		}
+	}
+	return idx
+}
+
+// parseParams parses the ESMTP parameters (RFC 5321, section 4.1.2).
+func parseParams(str string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, p := range strings.Fields(str) {
+		k, v, _ := strings.Cut(p, "=")
+		if !validKeyword(k) {
+			return params, fmt.Errorf("%w: %q", ErrInvalidParam, p)
+		}
+
+		k = strings.ToUpper(k)
+		if k == "ORCPT" || k == "ENVID" {
+			var err error
+			v, err = DecodeXtext(v)
+			if err != nil {
+				return params, fmt.Errorf("%w: %s", err, k)
+			}
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+// validKeyword reports if k is a valid esmtp-keyword:
+//
+//	esmtp-keyword = (ALPHA / DIGIT) *(ALPHA / DIGIT / "-")
+func validKeyword(k string) bool {
+	if k == "" || k[0] == '-' {
+		return false
+	}
+	for _, c := range k {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeXtext decodes xtext, as described in RFC 3461, section 4. For example
+// "a+2Bb" is decoded to "a+b".
+func DecodeXtext(str string) (string, error) {
+	if !strings.Contains(str, "+") {
+		return str, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] != '+' {
+			b.WriteByte(str[i])
+			continue
+		}
+
+		if i+2 >= len(str) || !isUpperHex(str[i+1]) || !isUpperHex(str[i+2]) {
+			return "", ErrInvalidXtext
+		}
+		b.WriteByte(unhex(str[i+1])<<4 | unhex(str[i+2]))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// EncodeXtext encodes str as xtext, as described in RFC 3461, section 4.
+func EncodeXtext(str string) string {
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c < '!' || c > '~' || c == '+' || c == '=' {
+			fmt.Fprintf(&b, "+%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isUpperHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	if c >= 'A' {
+		return c - 'A' + 10
+	}
+	return c - '0'
+}
@@ -0,0 +1,155 @@
+package mailaddress
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/teamwork/test"
+	"github.com/teamwork/test/diff"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := []struct {
+		in          string
+		expected    Path
+		expectedErr string
+	}{
+		{"<>", Path{Null: true}, ""},
+		{"<user@example.com>", Path{Address: Address{Address: "user@example.com", Raw: "user@example.com"}}, ""},
+		{"user@example.com", Path{Address: Address{Address: "user@example.com", Raw: "user@example.com"}}, ""},
+		{"<Postmaster>", Path{Address: Address{Address: "Postmaster", Raw: "Postmaster"}}, ""},
+		{`<"a@b"@example.com>`, Path{Address: Address{Address: `"a@b"@example.com`, Raw: `"a@b"@example.com`}}, ""},
+		{
+			"<@a.example,@b.example:user@c.example>",
+			Path{
+				Address: Address{Address: "user@c.example", Raw: "user@c.example"},
+				Route:   []string{"a.example", "b.example"},
+			},
+			"",
+		},
+
+		{"", Path{}, "empty path"},
+		{"  ", Path{}, "empty path"},
+		{"<user@example.com", Path{}, "no closing >"},
+		{"<user>", Path{}, "no domain"},
+		{"<@example.com>", Path{}, "source route without :"},
+		{"<@a.example,b.example:user@c.example>", Path{}, "invalid source route"},
+		{"<user@>", Path{}, "empty local part"},
+		{"<user@exa mple.com>", Path{}, "invalid domain"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParsePath(tc.in)
+			if !test.ErrorContains(err, tc.expectedErr) {
+				t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+			}
+			if tc.expectedErr != "" {
+				return
+			}
+			if d := diff.Diff(tc.expected, got); d != "" {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestParseSMTPCommand(t *testing.T) {
+	cases := []struct {
+		in             string
+		expectedVerb   string
+		expectedPath   string
+		expectedParams map[string]string
+		expectedErr    string
+	}{
+		{
+			"MAIL FROM:<user@example.com> SIZE=1234 BODY=8BITMIME SMTPUTF8\r\n",
+			"MAIL", "<user@example.com>",
+			map[string]string{"SIZE": "1234", "BODY": "8BITMIME", "SMTPUTF8": ""},
+			"",
+		},
+		{"mail from: <>", "MAIL", "<>", map[string]string{}, ""},
+		{"MAIL FROM:bare@example.com", "MAIL", "<bare@example.com>", map[string]string{}, ""},
+		{
+			"RCPT TO:<x@y.example> NOTIFY=SUCCESS,FAILURE ORCPT=rfc822;x+2Btag@y.example",
+			"RCPT", "<x@y.example>",
+			map[string]string{"NOTIFY": "SUCCESS,FAILURE", "ORCPT": "rfc822;x+tag@y.example"},
+			"",
+		},
+		{"RCPT TO:<Postmaster>", "RCPT", "<Postmaster>", map[string]string{}, ""},
+		{`RCPT TO:<"a> b"@example.com> notify=never`, "RCPT", `<"a> b"@example.com>`,
+			map[string]string{"NOTIFY": "never"}, ""},
+
+		{"HELO example.com", "", "", nil, "not a MAIL FROM"},
+		{"RCPT TO:<>", "", "", nil, "null path not allowed"},
+		{"MAIL FROM:<postmaster>", "", "", nil, "<Postmaster> not allowed"},
+		{"RCPT TO:<x@y.example> -FOO=bar", "", "", nil, "invalid ESMTP parameter"},
+		{"RCPT TO:<x@y.example> ORCPT=rfc822;x+2b", "", "", nil, "invalid xtext"},
+		{"RCPT TO:<x@y.example", "", "", nil, "no closing >"},
+		{"MAIL FROM:", "", "", nil, "empty path"},
+		{"MAIL FROM: SIZE=100", "", "", nil, "no domain"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseSMTPCommand(tc.in)
+			if !test.ErrorContains(err, tc.expectedErr) {
+				t.Fatalf("wrong error\nexpected: %#v\ngot     : %v\n", tc.expectedErr, err)
+			}
+			if tc.expectedErr != "" {
+				return
+			}
+
+			if got.Verb != tc.expectedVerb {
+				t.Errorf("wrong verb: %v", got.Verb)
+			}
+			if got.Path.String() != tc.expectedPath {
+				t.Errorf("wrong path: %v", got.Path)
+			}
+			if d := diff.Diff(tc.expectedParams, got.Params); d != "" {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func TestOriginalRecipient(t *testing.T) {
+	cmd, err := ParseSMTPCommand("RCPT TO:<x@example.com> ORCPT=rfc822;x+2Btag@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	typ, addr := cmd.OriginalRecipient()
+	if typ != "rfc822" || addr != "x+tag@example.com" {
+		t.Errorf("wrong ORCPT: %q %q", typ, addr)
+	}
+}
+
+func TestXtext(t *testing.T) {
+	cases := []struct {
+		in, expected string
+	}{
+		{"", ""},
+		{"user@example.com", "user@example.com"},
+		{"a+b=c", "a+2Bb+3Dc"},
+		{"a b", "a+20b"},
+		{"€", "+E2+82+AC"},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			enc := EncodeXtext(tc.in)
+			if enc != tc.expected {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", enc, tc.expected)
+			}
+
+			dec, err := DecodeXtext(enc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dec != tc.in {
+				t.Errorf("\nout:      %#v\nexpected: %#v\n", dec, tc.in)
+			}
+		})
+	}
+}
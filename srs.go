@@ -0,0 +1,220 @@
+package mailaddress
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- the SRS spec uses HMAC-SHA1.
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotSRS is used when an address isn't an SRS address.
+	ErrNotSRS = errors.New("not an SRS address")
+
+	// ErrSRSFormat is used when an SRS address is malformed.
+	ErrSRSFormat = errors.New("malformed SRS address")
+
+	// ErrSRSHash is used when the hash of an SRS address doesn't match.
+	ErrSRSHash = errors.New("invalid SRS hash")
+
+	// ErrSRSExpired is used when the timestamp of an SRS address is too old.
+	ErrSRSExpired = errors.New("SRS address expired")
+)
+
+const (
+	srsHashLength = 4
+	srsBase32     = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	// Timestamps are in days, encoded in two base32 characters.
+	srsTimeSlots = 1024
+)
+
+// SRS rewrites envelope senders with the Sender Rewriting Scheme, so that
+// forwarded mail passes SPF checks. See:
+// https://www.libsrs2.org/srs/srs.pdf
+//
+// A sender of user@example.com forwarded through forward.example is rewritten
+// to:
+//
+//	SRS0=HHHH=TT=example.com=user@forward.example
+//
+// Where HHHH is a HMAC-SHA1 hash and TT a timestamp in days. Addresses that
+// are already rewritten (SRS0 or SRS1) are rewritten to an SRS1 address, which
+// points back to the first forwarder:
+//
+//	SRS1=HHHH=forward.example==HHHH=TT=example.com=user@second.example
+type SRS struct {
+	// Secret used for the hash.
+	Secret []byte
+
+	// Domain is the forwarding domain which will be used as the domain for
+	// rewritten addresses.
+	Domain string
+
+	// MaxAge is the maximum age of an SRS0 address in Reverse(). The
+	// resolution is one day; the default is 21 days.
+	MaxAge time.Duration
+
+	now func() time.Time
+}
+
+// Forward rewrites the sender address. Addresses which already have our
+// Domain are returned as-is.
+func (s SRS) Forward(a Address) (Address, error) {
+	if err := a.Check(); err != nil {
+		return Address{}, err
+	}
+
+	domain := a.Domain()
+	if strings.EqualFold(domain, s.Domain) {
+		return a, nil
+	}
+
+	local := a.Local()
+	var rewritten string
+	switch srsPrefix(local) {
+	case "SRS0":
+		// SRS1=HHHH=first-forwarder==HHHH=TT=domain=local
+		rest := "=" + local[5:]
+		rewritten = "SRS1=" + s.hash(domain, rest) + "=" + domain + "=" + rest
+	case "SRS1":
+		// Keep the first forwarder, and just update the hash.
+		_, host, rest, err := splitSRS1(local)
+		if err != nil {
+			return Address{}, err
+		}
+		rewritten = "SRS1=" + s.hash(host, rest) + "=" + host + "=" + rest
+	default:
+		ts := s.timestamp()
+		rewritten = "SRS0=" + s.hash(ts, domain, local) + "=" + ts + "=" + domain + "=" + local
+	}
+
+	return Address{Name: a.Name, Address: rewritten + "@" + s.Domain}, nil
+}
+
+// Reverse the rewritten address in to the original address. An SRS0 address
+// is reversed to the original sender, and an SRS1 address is reversed to the
+// SRS0 address of the first forwarder.
+//
+// The hash and timestamp are validated case-insensitively, as some MTAs change
+// the case of the local part.
+func (s SRS) Reverse(a Address) (Address, error) {
+	local := a.Local()
+	switch srsPrefix(local) {
+	case "SRS0":
+		// SRS0=HHHH=TT=domain=local
+		parts := strings.SplitN(local[5:], "=", 4)
+		if len(parts) != 4 || parts[2] == "" || parts[3] == "" {
+			return Address{}, ErrSRSFormat
+		}
+		if !s.checkHash(parts[0], parts[1], parts[2], parts[3]) {
+			return Address{}, ErrSRSHash
+		}
+		if err := s.checkTimestamp(parts[1]); err != nil {
+			return Address{}, err
+		}
+		return Address{Name: a.Name, Address: parts[3] + "@" + parts[2]}, nil
+
+	case "SRS1":
+		// SRS1=HHHH=first-forwarder==HHHH=TT=domain=local
+		hash, host, rest, err := splitSRS1(local)
+		if err != nil {
+			return Address{}, err
+		}
+		if !s.checkHash(hash, host, rest) {
+			return Address{}, ErrSRSHash
+		}
+		return Address{Name: a.Name, Address: "SRS0" + rest + "@" + host}, nil
+
+	default:
+		return Address{}, ErrNotSRS
+	}
+}
+
+// splitSRS1 gets the hash, first forwarder host, and the SRS0 part from an
+// SRS1 local part. The SRS0 part always starts with =.
+func splitSRS1(local string) (hash, host, rest string, err error) {
+	parts := strings.SplitN(local[5:], "=", 3)
+	if len(parts) != 3 || parts[1] == "" || len(parts[2]) < 2 {
+		return "", "", "", ErrSRSFormat
+	}
+
+	// Accept the SRS0 separator being changed to + or - by the previous
+	// forwarder.
+	rest = parts[2]
+	if rest[0] == '=' || rest[0] == '+' || rest[0] == '-' {
+		rest = rest[1:]
+	}
+	return parts[0], parts[1], "=" + rest, nil
+}
+
+// srsPrefix gets the SRS prefix (SRS0 or SRS1), if any. Some MTAs use + or -
+// as the first separator, so accept those too.
+func srsPrefix(local string) string {
+	if len(local) < 5 || !strings.ContainsRune("=+-", rune(local[4])) {
+		return ""
+	}
+	switch p := strings.ToUpper(local[:4]); p {
+	case "SRS0", "SRS1":
+		return p
+	}
+	return ""
+}
+
+// hash gets the first srsHashLength characters of the base64-encoded
+// HMAC-SHA1 hash of the lower-cased data.
+func (s SRS) hash(data ...string) string {
+	mac := hmac.New(sha1.New, s.Secret)
+	for _, d := range data {
+		_, _ = mac.Write([]byte(strings.ToLower(d)))
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))[:srsHashLength]
+}
+
+func (s SRS) checkHash(hash string, data ...string) bool {
+	if len(hash) != srsHashLength {
+		return false
+	}
+	return subtle.ConstantTimeCompare(
+		[]byte(strings.ToLower(hash)),
+		[]byte(strings.ToLower(s.hash(data...)))) == 1
+}
+
+func (s SRS) today() int {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	return int(now().Unix()/86400) % srsTimeSlots
+}
+
+func (s SRS) timestamp() string {
+	t := s.today()
+	return string([]byte{srsBase32[t>>5&31], srsBase32[t&31]})
+}
+
+func (s SRS) checkTimestamp(ts string) error {
+	if len(ts) != 2 {
+		return ErrSRSFormat
+	}
+
+	ts = strings.ToUpper(ts)
+	hi, lo := strings.IndexByte(srsBase32, ts[0]), strings.IndexByte(srsBase32, ts[1])
+	if hi == -1 || lo == -1 {
+		return ErrSRSFormat
+	}
+
+	maxAge := s.MaxAge
+	if maxAge == 0 {
+		maxAge = 21 * 24 * time.Hour
+	}
+
+	// The timestamp wraps around every 1024 days.
+	age := (s.today() - (hi<<5 | lo) + srsTimeSlots) % srsTimeSlots
+	if age > int(maxAge/(24*time.Hour)) {
+		return ErrSRSExpired
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package mailaddress
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSRS(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := SRS{Secret: []byte("first"), Domain: "first.example", now: func() time.Time { return now }}
+	second := SRS{Secret: []byte("second"), Domain: "second.example", now: func() time.Time { return now }}
+	third := SRS{Secret: []byte("third"), Domain: "third.example", now: func() time.Time { return now }}
+
+	orig := Address{Name: "Martin", Address: "martin@example.com"}
+
+	srs0, err := first.Forward(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^SRS0=[^=]{4}=[A-Z2-7]{2}=example\.com=martin@first\.example$`).MatchString(srs0.Address) {
+		t.Errorf("wrong SRS0 address: %v", srs0.Address)
+	}
+	if srs0.Name != "Martin" {
+		t.Errorf("name not preserved: %v", srs0.Name)
+	}
+
+	srs1, err := second.Forward(srs0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^SRS1=[^=]{4}=first\.example==[^=]{4}=[A-Z2-7]{2}=example\.com=martin@second\.example$`).
+		MatchString(srs1.Address) {
+		t.Errorf("wrong SRS1 address: %v", srs1.Address)
+	}
+
+	// SRS1 → SRS1 only updates the hash and domain.
+	srs1Again, err := third.Forward(srs1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(srs1Again.Address, srs1.Address[9:strings.Index(srs1.Address, "@")]+"@third.example") {
+		t.Errorf("wrong SRS1 address: %v", srs1Again.Address)
+	}
+
+	// And reverse the whole lot.
+	rev, err := third.Reverse(srs1Again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.Address != srs0.Address {
+		t.Errorf("\nout:      %v\nexpected: %v\n", rev.Address, srs0.Address)
+	}
+
+	rev, err = second.Reverse(srs1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.Address != srs0.Address {
+		t.Errorf("\nout:      %v\nexpected: %v\n", rev.Address, srs0.Address)
+	}
+
+	// Case-insensitive.
+	rev, err = first.Reverse(Address{Address: strings.ToLower(srs0.Address)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev.Address != orig.Address {
+		t.Errorf("\nout:      %v\nexpected: %v\n", rev.Address, orig.Address)
+	}
+
+	// Our own domain isn't rewritten.
+	own := Address{Address: "x@first.example"}
+	out, err := first.Forward(own)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != own {
+		t.Errorf("rewrote own domain: %v", out)
+	}
+}
+
+func TestSRSReverseErrors(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	srs := SRS{Secret: []byte("secret"), Domain: "fwd.example", now: func() time.Time { return now }}
+	other := SRS{Secret: []byte("other"), Domain: "fwd.example", now: func() time.Time { return now }}
+
+	addr, err := srs.Forward(Address{Address: "martin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := srs
+	old.now = func() time.Time { return now.Add(-22 * 24 * time.Hour) }
+	oldAddr, err := old.Forward(Address{Address: "martin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wrap around at 1024 days.
+	wrapped := srs
+	wrapped.now = func() time.Time { return now.Add(-1030 * 24 * time.Hour) }
+	wrappedAddr, err := wrapped.Forward(Address{Address: "martin@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		srs      SRS
+		in       string
+		expected error
+	}{
+		{srs, "martin@example.com", ErrNotSRS},
+		{srs, "SRS0=abc@fwd.example", ErrSRSFormat},
+		{srs, "SRS1=abc@fwd.example", ErrSRSFormat},
+		{srs, "SRS0=XXXX=AA=example.com=martin@fwd.example", ErrSRSHash},
+		{srs, "SRS0=" + addr.Local()[5:9] + "=!!=example.com=martin@fwd.example", ErrSRSHash},
+		{other, addr.Address, ErrSRSHash},
+		{srs, oldAddr.Address, ErrSRSExpired},
+		{srs, wrappedAddr.Address, nil},
+		{srs, addr.Address, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := tc.srs.Reverse(Address{Address: tc.in})
+			if err != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSRSForwardInvalid(t *testing.T) {
+	_, err := SRS{Domain: "fwd.example"}.Forward(Address{Address: "invalid"})
+	if err != ErrNoEmail {
+		t.Errorf("wrong error: %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+package mailaddress
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SuggestOptions are the options for Suggest(). Any fields that are not set
+// use the value from DefaultSuggestOptions.
+type SuggestOptions struct {
+	// Domains are complete domains, such as "gmail.com".
+	Domains []string
+
+	// SecondLevelDomains are the second-level labels, such as "gmail".
+	SecondLevelDomains []string
+
+	// TopLevelDomains are the TLDs, which may have more than one label (e.g.
+	// "co.uk").
+	TopLevelDomains []string
+
+	// Threshold is the maximum edit distance for a suggestion from Domains
+	// and SecondLevelDomains.
+	Threshold int
+
+	// TopLevelThreshold is the maximum edit distance for TopLevelDomains. This
+	// is lower by default, as TLDs are short.
+	TopLevelThreshold int
+}
+
+// DefaultSuggestOptions are the default options for Suggest().
+var DefaultSuggestOptions = SuggestOptions{
+	Domains: []string{
+		"gmail.com", "googlemail.com", "yahoo.com", "yahoo.co.uk", "yahoo.ca",
+		"yahoo.fr", "yahoo.de", "hotmail.com", "hotmail.co.uk", "hotmail.ca",
+		"hotmail.fr", "hotmail.de", "outlook.com", "live.com", "live.ca",
+		"live.co.uk", "msn.com", "aol.com", "icloud.com", "me.com", "mac.com",
+		"protonmail.com", "proton.me", "gmx.com", "gmx.de", "gmx.net", "gmx.at",
+		"gmx.ch", "web.de", "mail.com", "email.com", "yandex.ru", "comcast.net",
+		"verizon.net", "att.net", "btinternet.com", "zoho.com", "fastmail.com",
+	},
+	SecondLevelDomains: []string{
+		"gmail", "googlemail", "yahoo", "hotmail", "outlook", "live", "aol",
+		"icloud", "protonmail", "gmx", "yandex", "comcast", "verizon", "zoho",
+		"fastmail",
+	},
+	TopLevelDomains: []string{
+		"com", "net", "org", "edu", "gov", "info", "io", "co", "me", "biz",
+		"app", "dev", "ai", "tv", "eu", "uk", "de", "fr", "nl", "be", "ch",
+		"at", "ie", "es", "pt", "it", "se", "no", "dk", "fi", "pl", "ru", "ca",
+		"au", "nz", "in", "jp", "cn", "br", "mx", "za", "us", "co.uk", "org.uk",
+		"ac.uk", "com.au", "co.nz", "co.jp", "com.br",
+	},
+	Threshold:         2,
+	TopLevelThreshold: 1,
+}
+
+// minSuggestLabel is the minimum length of a second-level label to suggest a
+// correction for it; too many real domains are a small edit away from a short
+// label (e.g. "zoo.com" and "zoho.com").
+const minSuggestLabel = 4
+
+// Suggest a correction for a mistyped domain, such as "gmial.com" →
+// "gmail.com". It first tries to match the complete domain against
+// opts.Domains, and then the second-level label and TLD separately.
+//
+// Only domains consisting of a second-level label and a TLD are corrected, so
+// "mail.example.com" is left alone. Labels that are known, either from
+// opts.SecondLevelDomains and opts.TopLevelDomains or from opts.Domains, are
+// never changed; e.g. "yahoo.ca" is not changed to "yahoo.com".
+//
+// The edit distance is the Damerau-Levenshtein distance (an adjacent
+// transposition counts as one edit). If there are multiple candidates with the
+// same distance the first one is used, so the output is deterministic.
+//
+// The bool return value reports if there is a suggestion.
+func Suggest(a Address, opts SuggestOptions) (Address, bool) {
+	if opts.Domains == nil {
+		opts.Domains = DefaultSuggestOptions.Domains
+	}
+	if opts.SecondLevelDomains == nil {
+		opts.SecondLevelDomains = DefaultSuggestOptions.SecondLevelDomains
+	}
+	if opts.TopLevelDomains == nil {
+		opts.TopLevelDomains = DefaultSuggestOptions.TopLevelDomains
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = DefaultSuggestOptions.Threshold
+	}
+	if opts.TopLevelThreshold == 0 {
+		opts.TopLevelThreshold = DefaultSuggestOptions.TopLevelThreshold
+	}
+
+	at := strings.Index(a.Address, "@")
+	if at < 1 {
+		return Address{}, false
+	}
+	domain := strings.ToLower(a.Domain())
+	if domain == "" {
+		return Address{}, false
+	}
+
+	slds, tlds := labelSet(opts.SecondLevelDomains), labelSet(opts.TopLevelDomains)
+	for _, d := range opts.Domains {
+		if dot := strings.IndexByte(d, '.'); dot > 0 {
+			slds[strings.ToLower(d[:dot])] = struct{}{}
+		}
+	}
+
+	sld, tld, ok := splitDomain(domain, tlds)
+	if !ok {
+		return Address{}, false
+	}
+	_, knownSLD := slds[sld]
+	_, knownTLD := tlds[tld]
+	fixSLD := !knownSLD && utf8.RuneCountInString(sld) >= minSuggestLabel
+
+	suggest := func(d string) (Address, bool) {
+		if d == domain {
+			return Address{}, false
+		}
+		return Address{Name: a.Name, Address: a.Local() + "@" + d}, true
+	}
+
+	// Only compare against domains which differ in the labels we're allowed to
+	// change.
+	domains := make([]string, 0, len(opts.Domains))
+	for _, d := range opts.Domains {
+		d = strings.ToLower(d)
+		dot := strings.IndexByte(d, '.')
+		if dot > 0 && (fixSLD || d[:dot] == sld) && (!knownTLD || d[dot+1:] == tld) {
+			domains = append(domains, d)
+		}
+	}
+	if d, dist := closest(domain, domains); dist == 0 {
+		return Address{}, false
+	} else if dist <= opts.Threshold {
+		return suggest(d)
+	}
+
+	if fixSLD {
+		if s, dist := closest(sld, opts.SecondLevelDomains); dist <= opts.Threshold {
+			sld = s
+		}
+	}
+	if !knownTLD {
+		s, dist := closest(tld, opts.TopLevelDomains)
+		if dist > opts.TopLevelThreshold {
+			return Address{}, false
+		}
+		tld = s
+	}
+	return suggest(sld + "." + tld)
+}
+
+// splitDomain splits the domain in the second-level label and the TLD, which
+// may have more than one label (e.g. "co.uk"). The TLD is found with the Public
+// Suffix List, and domains with more labels (e.g. "mail.example.com") are not
+// split.
+//
+// If the TLD is not in tlds then it may be a mistyped TLD with more than one
+// label (e.g. "co.ukk"), so the domain is split at the first dot.
+func splitDomain(domain string, tlds map[string]struct{}) (string, string, bool) {
+	suffix := PublicSuffixes().PublicSuffix(domain)
+	if _, ok := tlds[suffix]; !ok {
+		dot := strings.IndexByte(domain, '.')
+		if dot < 1 || dot == len(domain)-1 {
+			return "", "", false
+		}
+		return domain[:dot], domain[dot+1:], true
+	}
+
+	sld := strings.TrimSuffix(domain, "."+suffix)
+	if sld == domain || sld == "" || strings.Contains(sld, ".") {
+		return "", "", false
+	}
+	return sld, suffix, true
+}
+
+// labelSet creates a set of the lower-cased labels.
+func labelSet(labels []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(labels))
+	for _, l := range labels {
+		m[strings.ToLower(l)] = struct{}{}
+	}
+	return m
+}
+
+// closest gets the candidate with the lowest edit distance to s.
+func closest(s string, candidates []string) (string, int) {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		c = strings.ToLower(c)
+		d := editDistance(s, c)
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = c, d
+		}
+		if d == 0 {
+			break
+		}
+	}
+	if bestDist == -1 {
+		return "", int(^uint(0) >> 1)
+	}
+	return best, bestDist
+}
+
+// editDistance gets the Damerau-Levenshtein distance (the "optimal string
+// alignment" variant) between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
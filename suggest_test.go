@@ -0,0 +1,91 @@
+package mailaddress
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	cases := []struct {
+		in       string
+		opts     SuggestOptions
+		expected string
+	}{
+		{"", SuggestOptions{}, ""},
+		{"martin", SuggestOptions{}, ""},
+		{"@gmial.com", SuggestOptions{}, ""},
+		{"martin@gmail.com", SuggestOptions{}, ""},
+		{"martin@GMAIL.com", SuggestOptions{}, ""},
+		{"martin@example.com", SuggestOptions{}, ""},
+		{"martin@example.xyz", SuggestOptions{}, ""},
+		{"martin@localhost", SuggestOptions{}, ""},
+		{"martin@yahoo.co", SuggestOptions{}, ""},
+		{"martin@mail.example.com", SuggestOptions{}, ""},
+		{"martin@mail.acme.org", SuggestOptions{}, ""},
+		{"martin@mial.example.cmo", SuggestOptions{}, ""},
+		{"martin@email.com", SuggestOptions{}, ""},
+		{"martin@yahoo.ca", SuggestOptions{}, ""},
+		{"martin@hotmail.ca", SuggestOptions{}, ""},
+		{"martin@live.ca", SuggestOptions{}, ""},
+		{"martin@gmx.net", SuggestOptions{}, ""},
+		{"martin@zoo.com", SuggestOptions{}, ""},
+		{"martin@me.org", SuggestOptions{}, ""},
+		{"martin@mail.org", SuggestOptions{}, ""},
+		{"martin@gmial.xyz", SuggestOptions{}, ""},
+
+		{"martin@gmial.com", SuggestOptions{}, "martin@gmail.com"},
+		{"martin@GMAIL.CMO", SuggestOptions{}, "martin@gmail.com"},
+		{"martin@hotmial.co.uk", SuggestOptions{}, "martin@hotmail.co.uk"},
+		{"martin@aol.cmo", SuggestOptions{}, "martin@aol.com"},
+		{"martin@exmaple.cmo", SuggestOptions{}, "martin@exmaple.com"},
+		{"martin@example.con", SuggestOptions{}, "martin@example.com"},
+		{"martin@example.co.ukk", SuggestOptions{}, "martin@example.co.uk"},
+		{"martin@protonmial.ch", SuggestOptions{}, "martin@protonmail.ch"},
+
+		{"martin@gmial.com", SuggestOptions{Domains: []string{"example.com"}, SecondLevelDomains: []string{"x"}}, ""},
+		{"martin@exmaple.com", SuggestOptions{Domains: []string{"example.com"}}, "martin@example.com"},
+		{"martin@gmaaaail.com", SuggestOptions{}, ""},
+		{"martin@gmaaaail.com", SuggestOptions{Threshold: 3}, "martin@gmail.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out, ok := Suggest(Address{Name: "Martin", Address: tc.in}, tc.opts)
+			if ok != (tc.expected != "") {
+				t.Fatalf("ok is %t: %v", ok, out)
+			}
+			if out.Address != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out.Address, tc.expected)
+			}
+			if ok && out.Name != "Martin" {
+				t.Errorf("name not preserved: %v", out.Name)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"gmial", "gmail", 1},
+		{"gmal", "gmail", 1},
+		{"gmaill", "gmail", 1},
+		{"kitten", "sitting", 3},
+		{"ca", "abc", 3},
+		{"ü", "u", 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s-%s", tc.a, tc.b), func(t *testing.T) {
+			out := editDistance(tc.a, tc.b)
+			if out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package mailaddress
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Maximum lengths in octets, from RFC 5321 section 4.5.3.1 and RFC 1035
+// section 2.3.4. The maximum path length is 256 octets including the angle
+// brackets, which leaves 254 for the address.
+//
+// The lengths are of the wire form: the UTF-8 encoded local part and the
+// IDNA-encoded domain.
+const (
+	MaxLocalLength   = 64
+	MaxAddressLength = 254
+	MaxLabelLength   = 63
+)
+
+// checkLength reports if the address is within the maximum lengths.
+func checkLength(addr string) bool {
+	at := strings.IndexByte(addr, '@')
+	if at == -1 {
+		return len(addr) <= MaxLocalLength
+	}
+
+	if at > MaxLocalLength || wireLength(addr, at) > MaxAddressLength {
+		return false
+	}
+	for _, l := range strings.Split(addr[at+1:], ".") {
+		if wireLength(l, -1) > MaxLabelLength {
+			return false
+		}
+	}
+	return true
+}
+
+// ViolationCode is the type of a validation problem.
+type ViolationCode string
+
+// Violation codes.
+const (
+	VEmpty             ViolationCode = "empty"               // Address is empty.
+	VMissingAt         ViolationCode = "missing_at"          // There is no @.
+	VEmptyLocal        ViolationCode = "empty_local"         // Nothing before the @.
+	VEmptyDomain       ViolationCode = "empty_domain"        // Nothing after the @.
+	VLocalTooLong      ViolationCode = "local_too_long"      // Local part is longer than 64 octets.
+	VTooLong           ViolationCode = "too_long"            // Address is longer than 254 octets.
+	VLabelTooLong      ViolationCode = "label_too_long"      // Domain label is longer than 63 octets.
+	VEmptyLabel        ViolationCode = "empty_label"         // Domain has an empty label (e.g. "a..b").
+	VConsecutiveDots   ViolationCode = "consecutive_dots"    // Local part has consecutive dots.
+	VLeadingDot        ViolationCode = "leading_dot"         // Local part or domain starts with a dot.
+	VTrailingDot       ViolationCode = "trailing_dot"        // Local part or domain ends with a dot.
+	VInvalidCharacter  ViolationCode = "invalid_character"   // Character is not allowed.
+	VSingleLabelDomain ViolationCode = "single_label_domain" // Domain has only one label (e.g. "localhost").
+)
+
+var violationMessages = map[ViolationCode]string{
+	VEmpty:             "address is empty",
+	VMissingAt:         "address has no @",
+	VEmptyLocal:        "nothing before the @",
+	VEmptyDomain:       "nothing after the @",
+	VLocalTooLong:      fmt.Sprintf("part before the @ is longer than %d bytes", MaxLocalLength),
+	VTooLong:           fmt.Sprintf("address is longer than %d bytes", MaxAddressLength),
+	VLabelTooLong:      fmt.Sprintf("domain part is longer than %d bytes", MaxLabelLength),
+	VEmptyLabel:        "domain has an empty part",
+	VConsecutiveDots:   "two or more dots in a row",
+	VLeadingDot:        "starts with a dot",
+	VTrailingDot:       "ends with a dot",
+	VInvalidCharacter:  "invalid character",
+	VSingleLabelDomain: "domain has no dot",
+}
+
+// Violation is a single problem with an address.
+type Violation struct {
+	Code ViolationCode
+
+	// Position is the character (not byte) offset in Address.Address where
+	// the problem is, or -1 if it doesn't apply to a specific position.
+	Position int
+
+	// Char is the invalid character for VInvalidCharacter.
+	Char rune
+}
+
+// Error formats the violation as a human-readable message.
+func (v Violation) Error() string {
+	msg := violationMessages[v.Code]
+	if v.Code == VInvalidCharacter {
+		msg = fmt.Sprintf("%s %q", msg, v.Char)
+	}
+	if v.Position > -1 {
+		msg = fmt.Sprintf("%s at position %d", msg, v.Position)
+	}
+	return msg
+}
+
+// ValidateOptions are options for Validate().
+type ValidateOptions struct {
+	// AllowSingleLabelDomain allows domains such as "localhost".
+	AllowSingleLabelDomain bool
+}
+
+// Validate the address and report all problems. This is the same as Valid(),
+// except that it explains why an address is invalid, and it's stricter about
+// dots in the local part (e.g. "a..b@example.com" is accepted by Valid(), but
+// not by Validate()).
+//
+// The returned slice is nil if there are no problems.
+func Validate(a Address, opts ValidateOptions) []Violation {
+	if a.Address == "" {
+		return []Violation{{Code: VEmpty, Position: -1}}
+	}
+
+	v := validator{addr: a.Address}
+	at := strings.IndexByte(v.addr, '@')
+	if at > -1 && wireLength(v.addr, at) > MaxAddressLength {
+		v.add(VTooLong, -1, 0)
+	}
+
+	if at == -1 {
+		v.add(VMissingAt, -1, 0)
+		v.local(v.addr)
+		return v.v
+	}
+
+	v.local(v.addr[:at])
+	v.domain(at+1, opts)
+	return v.v
+}
+
+// wireLength gets the length of addr with the IDNA-encoded domain; the domain
+// starts after at. The entire string is treated as a domain if at is -1.
+func wireLength(addr string, at int) int {
+	domain, err := domainToASCII(addr[at+1:])
+	if err != nil {
+		return len(addr)
+	}
+	return at + 1 + len(domain)
+}
+
+type validator struct {
+	addr string
+	v    []Violation
+}
+
+// add a violation at the byte position pos.
+func (v *validator) add(code ViolationCode, pos int, c rune) {
+	if pos > -1 {
+		pos = utf8.RuneCountInString(v.addr[:pos])
+	}
+	v.v = append(v.v, Violation{Code: code, Position: pos, Char: c})
+}
+
+func (v *validator) local(local string) {
+	if local == "" {
+		v.add(VEmptyLocal, 0, 0)
+		return
+	}
+
+	if len(local) > MaxLocalLength {
+		v.add(VLocalTooLong, -1, 0)
+	}
+	if local[0] == '.' {
+		v.add(VLeadingDot, 0, 0)
+	}
+	if len(local) > 1 && local[len(local)-1] == '.' {
+		v.add(VTrailingDot, len(local)-1, 0)
+	}
+	if i := strings.Index(local, ".."); i > -1 {
+		v.add(VConsecutiveDots, i, 0)
+	}
+
+	for i, c := range local {
+		// Same characters as Valid() rejects.
+		if isSpace(c) || strings.ContainsRune("<>@;", c) {
+			v.add(VInvalidCharacter, i, c)
+		}
+	}
+}
+
+func (v *validator) domain(offset int, opts ValidateOptions) {
+	domain := v.addr[offset:]
+	if domain == "" {
+		v.add(VEmptyDomain, offset, 0)
+		return
+	}
+
+	if domain[0] == '.' {
+		v.add(VLeadingDot, offset, 0)
+	}
+	if len(domain) > 1 && domain[len(domain)-1] == '.' {
+		v.add(VTrailingDot, offset+len(domain)-1, 0)
+	}
+
+	labels := strings.Split(domain, ".")
+	pos := offset
+	for i, l := range labels {
+		switch {
+		case l == "" && i > 0 && i < len(labels)-1:
+			v.add(VEmptyLabel, pos, 0)
+		case wireLength(l, -1) > MaxLabelLength:
+			v.add(VLabelTooLong, pos, 0)
+		}
+
+		for j, c := range l {
+			if !unicode.IsLetter(c) && !(c >= '0' && c <= '9') && c != '-' {
+				v.add(VInvalidCharacter, pos+j, c)
+			}
+		}
+		pos += len(l) + 1
+	}
+
+	if len(labels) == 1 && !opts.AllowSingleLabelDomain {
+		v.add(VSingleLabelDomain, -1, 0)
+	}
+}
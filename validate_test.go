@@ -0,0 +1,136 @@
+package mailaddress
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teamwork/test/diff"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		in       string
+		opts     ValidateOptions
+		expected []Violation
+	}{
+		{"martin@example.com", ValidateOptions{}, nil},
+		{"f€@ü.русские", ValidateOptions{}, nil},
+		{"martin@localhost", ValidateOptions{AllowSingleLabelDomain: true}, nil},
+		{strings.Repeat("a", 64) + "@example.com", ValidateOptions{}, nil},
+
+		{"", ValidateOptions{}, []Violation{{VEmpty, -1, 0}}},
+		{"martin", ValidateOptions{}, []Violation{{VMissingAt, -1, 0}}},
+		{"@example.com", ValidateOptions{}, []Violation{{VEmptyLocal, 0, 0}}},
+		{"martin@", ValidateOptions{}, []Violation{{VEmptyDomain, 7, 0}}},
+		{"martin@localhost", ValidateOptions{}, []Violation{{VSingleLabelDomain, -1, 0}}},
+		{strings.Repeat("a", 65) + "@example.com", ValidateOptions{}, []Violation{{VLocalTooLong, -1, 0}}},
+		{strings.Repeat("€", 22) + "@example.com", ValidateOptions{}, []Violation{{VLocalTooLong, -1, 0}}},
+		{
+			"a@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) +
+				"." + strings.Repeat("d", 61) + ".com",
+			ValidateOptions{},
+			[]Violation{{VTooLong, -1, 0}},
+		},
+		{"a@" + strings.Repeat("a", 64) + ".com", ValidateOptions{}, []Violation{{VLabelTooLong, 2, 0}}},
+		{"a@" + strings.Repeat("bü", 29) + ".com", ValidateOptions{}, []Violation{{VLabelTooLong, 2, 0}}},
+		{"a@" + strings.Repeat("bü", 28) + ".com", ValidateOptions{}, nil},
+		{
+			// Longer than 254 bytes as UTF-8, but not in the IDNA-encoded form.
+			"a@" + strings.Repeat(strings.Repeat("中", 20)+".", 5) + "com",
+			ValidateOptions{},
+			nil,
+		},
+		{"martin@example..com", ValidateOptions{}, []Violation{{VEmptyLabel, 15, 0}}},
+		{"martin..t@example.com", ValidateOptions{}, []Violation{{VConsecutiveDots, 6, 0}}},
+		{".martin@example.com", ValidateOptions{}, []Violation{{VLeadingDot, 0, 0}}},
+		{"martin.@example.com", ValidateOptions{}, []Violation{{VTrailingDot, 6, 0}}},
+		{"martin@.example.com", ValidateOptions{}, []Violation{{VLeadingDot, 7, 0}}},
+		{"martin@example.com.", ValidateOptions{}, []Violation{{VTrailingDot, 18, 0}}},
+		{"mar tin@example.com", ValidateOptions{}, []Violation{{VInvalidCharacter, 3, ' '}}},
+		{"mär<tin@exa_mple.com", ValidateOptions{}, []Violation{
+			{VInvalidCharacter, 3, '<'},
+			{VInvalidCharacter, 11, '_'},
+		}},
+		{"a@b@example.com", ValidateOptions{}, []Violation{{VInvalidCharacter, 3, '@'}}},
+		{"user@[IPv6:2001:DB8::1]", ValidateOptions{}, []Violation{
+			{VInvalidCharacter, 5, '['},
+			{VInvalidCharacter, 10, ':'},
+			{VInvalidCharacter, 15, ':'},
+			{VInvalidCharacter, 19, ':'},
+			{VInvalidCharacter, 20, ':'},
+			{VInvalidCharacter, 22, ']'},
+			{VSingleLabelDomain, -1, 0},
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			out := Validate(Address{Address: tc.in}, tc.opts)
+			if d := diff.Diff(tc.expected, out); d != "" {
+				t.Errorf("%s\n%v", d, out)
+			}
+		})
+	}
+}
+
+func TestValidateValidAddresses(t *testing.T) {
+	for test, expected := range validAddresses {
+		t.Run(test, func(t *testing.T) {
+			if out := Validate(expected, ValidateOptions{}); out != nil {
+				t.Errorf("%v", out)
+			}
+		})
+	}
+}
+
+func TestValidateConsistent(t *testing.T) {
+	cases := []string{
+		"a@" + strings.Repeat(strings.Repeat("中", 20)+".", 5) + "com",
+		"a@" + strings.Repeat(strings.Repeat("中", 40)+".", 5) + "com",
+		"a@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) +
+			"." + strings.Repeat("d", 61) + ".com",
+
+		// Character classes.
+		"a b@example.com",
+		"a\tb@example.com",
+		"a\u00a0b@example.com",
+		"a\u2028b@example.com",
+		"a\x01b@example.com",
+		"a\x7fb@example.com",
+		"a\xffb@example.com",
+		"a<b@example.com",
+		"a;b@example.com",
+		"mär+tïn@bücher.example",
+		"a@exa_mple.com",
+		"a@exa mple.com",
+		"a@exa\u00a0mple.com",
+		"a@١٢٣.com",
+	}
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			a := Address{Address: tc}
+			if valid, violations := a.Valid(), Validate(a, ValidateOptions{}); valid != (violations == nil) {
+				t.Errorf("Valid() is %t, but Validate() returned %v", valid, violations)
+			}
+		})
+	}
+}
+
+func TestViolationError(t *testing.T) {
+	cases := []struct {
+		in       Violation
+		expected string
+	}{
+		{Violation{VEmpty, -1, 0}, "address is empty"},
+		{Violation{VLabelTooLong, 4, 0}, "domain part is longer than 63 bytes at position 4"},
+		{Violation{VInvalidCharacter, 3, ' '}, "invalid character ' ' at position 3"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			if out := tc.in.Error(); out != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package mailaddress
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VERP encodes and decodes Variable Envelope Return Paths, which encode the
+// recipient in the return path so that bounces can be traced to the recipient.
+// For example with the return path bounces@example.com and recipient
+// user@example.net:
+//
+//	bounces+user=example.net@example.com
+//
+// The @ in the recipient is replaced with =, and any +, =, or delimiter
+// characters in the recipient's local part are escaped as +XX (the hex value),
+// the same as xtext.
+type VERP struct {
+	// Delimiter between the return path's local part and the recipient; this
+	// should be one of +, -, or =.
+	//
+	// If the delimiter is +, then the return path's local part can't contain
+	// a +. This is the same as WithoutTag(), which is what most MTAs
+	// implement.
+	Delimiter byte
+}
+
+// DefaultVERP uses + as the delimiter.
+var DefaultVERP = VERP{Delimiter: '+'}
+
+// VERPEncode encodes the recipient in the returnPath with DefaultVERP.
+func VERPEncode(returnPath, recipient Address) Address {
+	return DefaultVERP.Encode(returnPath, recipient)
+}
+
+// VERPDecode decodes a VERP address with DefaultVERP.
+func VERPDecode(a Address) (returnPath, recipient Address, ok bool) {
+	return DefaultVERP.Decode(a)
+}
+
+// Encode the recipient in the returnPath. The returned address will have an
+// error set if either returnPath or recipient are not valid.
+func (v VERP) Encode(returnPath, recipient Address) Address {
+	if err := returnPath.Check(); err != nil {
+		return Address{err: err}
+	}
+	if err := recipient.Check(); err != nil {
+		return Address{err: err}
+	}
+
+	var b strings.Builder
+	b.WriteString(returnPath.Local())
+	b.WriteByte(v.Delimiter)
+	for _, c := range []byte(recipient.Local()) {
+		if c == '+' || c == '=' || c == v.Delimiter {
+			fmt.Fprintf(&b, "+%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	b.WriteByte('=')
+	b.WriteString(recipient.Domain())
+	b.WriteByte('@')
+	b.WriteString(returnPath.Domain())
+
+	return Address{Address: b.String()}
+}
+
+// Decode the recipient from a VERP address. The ok return value is false if
+// this doesn't look like a VERP address.
+func (v VERP) Decode(a Address) (returnPath, recipient Address, ok bool) {
+	local := a.Local()
+	eq := strings.LastIndexByte(local, '=')
+	if eq == -1 || !a.Valid() {
+		return Address{}, Address{}, false
+	}
+
+	head := local[:eq]
+	var d int
+	if v.Delimiter == '+' {
+		d = strings.IndexByte(head, v.Delimiter)
+	} else {
+		d = strings.LastIndexByte(head, v.Delimiter)
+	}
+	if d < 1 || d == len(head)-1 {
+		return Address{}, Address{}, false
+	}
+
+	rcptLocal, err := DecodeXtext(head[d+1:])
+	if err != nil {
+		return Address{}, Address{}, false
+	}
+
+	returnPath = Address{Address: head[:d] + "@" + a.Domain()}
+	recipient = Address{Address: rcptLocal + "@" + local[eq+1:]}
+	if !recipient.Valid() {
+		return Address{}, Address{}, false
+	}
+	return returnPath, recipient, true
+}
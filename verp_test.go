@@ -0,0 +1,72 @@
+package mailaddress
+
+import "testing"
+
+func TestVERP(t *testing.T) {
+	cases := []struct {
+		delim                 byte
+		returnPath, recipient string
+		expected              string
+	}{
+		{'+', "bounces@example.com", "user@example.net", "bounces+user=example.net@example.com"},
+		{'+', "bounces@example.com", "user+tag@ex-ample.net", "bounces+user+2Btag=ex-ample.net@example.com"},
+		{'+', "bounces@example.com", "a=b@example.net", "bounces+a+3Db=example.net@example.com"},
+		{'-', "bounces-list@example.com", "john-doe@ex-ample.net", "bounces-list-john+2Ddoe=ex-ample.net@example.com"},
+		{'-', "bounces@example.com", "a+b=c@example.net", "bounces-a+2Bb+3Dc=example.net@example.com"},
+		{'=', "bounces=x@example.com", "a=b@example.net", "bounces=x=a+3Db=example.net@example.com"},
+		{'=', "bounces@example.com", "a-b@example.net", "bounces=a-b=example.net@example.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			v := VERP{Delimiter: tc.delim}
+			out := v.Encode(Address{Address: tc.returnPath}, Address{Address: tc.recipient})
+			if out.Address != tc.expected {
+				t.Errorf("\nout:      %v\nexpected: %v\n", out.Address, tc.expected)
+			}
+
+			returnPath, recipient, ok := v.Decode(out)
+			if !ok {
+				t.Fatal("ok is false")
+			}
+			if returnPath.Address != tc.returnPath {
+				t.Errorf("wrong returnPath\nout:      %v\nexpected: %v\n", returnPath.Address, tc.returnPath)
+			}
+			if recipient.Address != tc.recipient {
+				t.Errorf("wrong recipient\nout:      %v\nexpected: %v\n", recipient.Address, tc.recipient)
+			}
+		})
+	}
+}
+
+func TestVERPEncodeInvalid(t *testing.T) {
+	out := VERPEncode(Address{Address: "bounces@example.com"}, Address{Address: "invalid"})
+	if out.Error() == nil {
+		t.Error("no error")
+	}
+	out = VERPEncode(Address{Address: "invalid"}, Address{Address: "user@example.com"})
+	if out.Error() == nil {
+		t.Error("no error")
+	}
+}
+
+func TestVERPDecodeInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"bounces@example.com",
+		"bounces+tag@example.com",
+		"+user=example.net@example.com",
+		"bounces+=example.net@example.com",
+		"bounces+user=localhost@example.com",
+		"bounces+user+2=example.net@example.com",
+	}
+
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			_, _, ok := VERPDecode(Address{Address: tc})
+			if ok {
+				t.Error("ok is true")
+			}
+		})
+	}
+}